@@ -40,12 +40,93 @@ type ResourceGraphDefinitionSpec struct {
 	// ServiceAccount configuration for controller impersonation.
 	// Key is the namespace, value is the service account name to use.
 	// Special key "*" defines the default service account for any
-	// namespace not explicitly mapped.
+	// namespace not explicitly mapped. A value can also reference a service
+	// account of another namespace as "<namespace>/<name>", cluster scoped
+	// instances only use the "*" key, which must be in that form.
 	//
 	// +kubebuilder:validation:Optional
 	DefaultServiceAccounts map[string]string `json:"defaultServiceAccounts,omitempty"`
+	// Propagate lists the instance labels and annotations that are copied
+	// onto every resource created for the instance.
+	//
+	// +kubebuilder:validation:Optional
+	Propagate *Propagation `json:"propagate,omitempty"`
+	// ReadyGate lists the IDs of the resources whose readiness determines
+	// the readiness of the instance. When set, the other resources are still
+	// reconciled and reported, but the instance doesn't wait for them to be
+	// ready. When empty, all the resources gate the instance readiness.
+	//
+	// +kubebuilder:validation:Optional
+	ReadyGate []string `json:"readyGate,omitempty"`
+	// ReadyExpression is a standalone CEL expression over the resources,
+	// e.g. ${deployment.status.availableReplicas > 0 && database.status.ready},
+	// that must evaluate to true for the instance to be ready. When set, the
+	// readiness of the individual resources only gates the instance readiness
+	// for the resources listed in ReadyGate. Resources excluded by their
+	// includeWhen conditions are null in the expression.
+	//
+	// +kubebuilder:validation:Optional
+	ReadyExpression string `json:"readyExpression,omitempty"`
+	// Overlays patch the resource templates depending on the value of an
+	// instance spec field, e.g. to declare environment specific variants of
+	// the resources.
+	//
+	// +kubebuilder:validation:Optional
+	Overlays *Overlays `json:"overlays,omitempty"`
 }
 
+// Overlays patch the resource templates depending on the value of an instance
+// spec field.
+type Overlays struct {
+	// Field is the path of the string instance spec field selecting the
+	// overlay, e.g. `environment` for `schema.spec.environment`.
+	//
+	// +kubebuilder:validation:Required
+	Field string `json:"field"`
+	// Variants maps the values of the field to the patches applied to the
+	// resource templates when the instance field has that value. Each patch
+	// is keyed by the resource ID, and is merged into the resource template
+	// as a JSON merge patch: objects are merged, while lists and scalars
+	// replace the template values, and null removes a field. Lists of objects
+	// with a name, e.g. containers, are merged by name instead.
+	//
+	// +kubebuilder:validation:Required
+	Variants map[string]map[string]runtime.RawExtension `json:"variants"`
+}
+
+// Propagation selects the instance labels and annotations to propagate to
+// the resources created for the instance.
+type Propagation struct {
+	// Labels is the list of instance label keys to propagate.
+	//
+	// +kubebuilder:validation:Optional
+	Labels []string `json:"labels,omitempty"`
+	// Annotations is the list of instance annotation keys to propagate.
+	//
+	// +kubebuilder:validation:Optional
+	Annotations []string `json:"annotations,omitempty"`
+	// Precedence decides which value wins when a resource template sets a
+	// propagated label or annotation: the instance value (Instance) or the
+	// template value (Template). Defaults to Instance.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Instance;Template
+	Precedence PropagationPrecedence `json:"precedence,omitempty"`
+}
+
+// PropagationPrecedence decides which value wins when a resource template sets
+// a propagated label or annotation.
+type PropagationPrecedence string
+
+const (
+	// PropagationPrecedenceInstance makes the instance values override the
+	// template values.
+	PropagationPrecedenceInstance PropagationPrecedence = "Instance"
+	// PropagationPrecedenceTemplate keeps the template values, the instance
+	// values are only set when the template doesn't set them.
+	PropagationPrecedenceTemplate PropagationPrecedence = "Template"
+)
+
 // Schema represents the attributes that define an instance of
 // a resourcegraphdefinition.
 type Schema struct {
@@ -69,6 +150,14 @@ type Schema struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="kro.run"
 	Group string `json:"group,omitempty"`
+	// The scope of the generated CRD, either Namespaced or Cluster. Instances
+	// of a cluster scoped resourcegraphdefinition have no namespace.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Namespaced;Cluster
+	// +kubebuilder:default=Namespaced
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="scope is immutable"
+	Scope string `json:"scope,omitempty"`
 	// The spec of the resourcegraphdefinition. Typically, this is the spec of
 	// the CRD that the resourcegraphdefinition is managing. This is adhering
 	// to the SimpleSchema spec
@@ -86,6 +175,19 @@ type Schema struct {
 	// Validation is a list of validation rules that are applied to the
 	// resourcegraphdefinition.
 	Validation []Validation `json:"validation,omitempty"`
+	// MutuallyExclusive is a list of groups of spec fields, at most one
+	// field of each group can be set on an instance. The groups are
+	// enforced by validation rules on the generated CRD.
+	//
+	// +kubebuilder:validation:Optional
+	MutuallyExclusive [][]string `json:"mutuallyExclusive,omitempty"`
+	// ComputedDefaults maps spec fields to standalone CEL expressions over
+	// other spec fields (e.g. ${schema.spec.a + schema.spec.b}). When an
+	// instance leaves one of these fields unset, the expression is evaluated
+	// and its value is used while resolving the resources.
+	//
+	// +kubebuilder:validation:Optional
+	ComputedDefaults map[string]string `json:"computedDefaults,omitempty"`
 	// AdditionalPrinterColumns defines additional printer columns
 	// that will be passed down to the created CRD. If set, no
 	// default printer columns will be added to the created CRD,
@@ -118,6 +220,25 @@ type ExternalRef struct {
 	Kind string `json:"kind"`
 	// +kubebuilder:validation:Required
 	Metadata ExternalRefMetadata `json:"metadata"`
+	// DeleteWithInstance makes kro delete the referenced resource when the
+	// instance is deleted. The resource is still never created or updated.
+	//
+	// +kubebuilder:validation:Optional
+	DeleteWithInstance bool `json:"deleteWithInstance,omitempty"`
+}
+
+// ReadyWhenJSONPath is a JSONPath based readiness check. It matches when the
+// JSONPath expression, evaluated against the live object, yields the expected
+// value.
+type ReadyWhenJSONPath struct {
+	// Path is a JSONPath expression, e.g `{.status.phase}`.
+	//
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+	// Value is the expected result of the JSONPath expression, e.g `Running`.
+	//
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
 }
 
 // +kubebuilder:validation:XValidation:rule="(has(self.template) && !has(self.externalRef)) || (!has(self.template) && has(self.externalRef))",message="exactly one of template or externalRef must be provided"
@@ -130,10 +251,42 @@ type Resource struct {
 	ExternalRef *ExternalRef `json:"externalRef,omitempty"`
 	// +kubebuilder:validation:Optional
 	ReadyWhen []string `json:"readyWhen,omitempty"`
+	// ReadyWhenJSONPath is a simpler alternative to ReadyWhen. The resource is
+	// considered ready when all the JSONPath checks match the live object.
+	//
+	// +kubebuilder:validation:Optional
+	ReadyWhenJSONPath []ReadyWhenJSONPath `json:"readyWhenJSONPath,omitempty"`
 	// +kubebuilder:validation:Optional
 	IncludeWhen []string `json:"includeWhen,omitempty"`
+	// Reconcile decides whether the resource is updated when it already
+	// exists (Always), or created once and then left alone (Once), e.g. for
+	// bootstrap jobs. Defaults to Always.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Always;Once
+	Reconcile ReconcilePolicy `json:"reconcile,omitempty"`
+	// DeletionPropagation is the propagation policy used to delete the
+	// resource with the instance, e.g. Orphan to leave the Pods of a
+	// Deployment in place. Defaults to the policy configured on the
+	// controller.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Orphan;Background;Foreground
+	DeletionPropagation metav1.DeletionPropagation `json:"deletionPropagation,omitempty"`
 }
 
+// ReconcilePolicy decides whether kro updates a resource that already exists.
+type ReconcilePolicy string
+
+const (
+	// ReconcilePolicyAlways makes kro update the resource on every
+	// reconciliation.
+	ReconcilePolicyAlways ReconcilePolicy = "Always"
+	// ReconcilePolicyOnce makes kro create the resource and never update it
+	// afterwards. The resource is still deleted with the instance.
+	ReconcilePolicyOnce ReconcilePolicy = "Once"
+)
+
 // ResourceGraphDefinitionState defines the state of the resource graph definition.
 type ResourceGraphDefinitionState string
 
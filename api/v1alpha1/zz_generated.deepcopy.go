@@ -105,6 +105,79 @@ func (in *ExternalRefMetadata) DeepCopy() *ExternalRefMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Overlays) DeepCopyInto(out *Overlays) {
+	*out = *in
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make(map[string]map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			var outVal map[string]runtime.RawExtension
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]runtime.RawExtension, len(*in))
+				for key, val := range *in {
+					(*out)[key] = *val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Overlays.
+func (in *Overlays) DeepCopy() *Overlays {
+	if in == nil {
+		return nil
+	}
+	out := new(Overlays)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Propagation) DeepCopyInto(out *Propagation) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Propagation.
+func (in *Propagation) DeepCopy() *Propagation {
+	if in == nil {
+		return nil
+	}
+	out := new(Propagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadyWhenJSONPath) DeepCopyInto(out *ReadyWhenJSONPath) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadyWhenJSONPath.
+func (in *ReadyWhenJSONPath) DeepCopy() *ReadyWhenJSONPath {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadyWhenJSONPath)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
@@ -119,6 +192,11 @@ func (in *Resource) DeepCopyInto(out *Resource) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReadyWhenJSONPath != nil {
+		in, out := &in.ReadyWhenJSONPath, &out.ReadyWhenJSONPath
+		*out = make([]ReadyWhenJSONPath, len(*in))
+		copy(*out, *in)
+	}
 	if in.IncludeWhen != nil {
 		in, out := &in.IncludeWhen, &out.IncludeWhen
 		*out = make([]string, len(*in))
@@ -221,6 +299,21 @@ func (in *ResourceGraphDefinitionSpec) DeepCopyInto(out *ResourceGraphDefinition
 			(*out)[key] = val
 		}
 	}
+	if in.Propagate != nil {
+		in, out := &in.Propagate, &out.Propagate
+		*out = new(Propagation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadyGate != nil {
+		in, out := &in.ReadyGate, &out.ReadyGate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = new(Overlays)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGraphDefinitionSpec.
@@ -298,6 +391,24 @@ func (in *Schema) DeepCopyInto(out *Schema) {
 		*out = make([]Validation, len(*in))
 		copy(*out, *in)
 	}
+	if in.MutuallyExclusive != nil {
+		in, out := &in.MutuallyExclusive, &out.MutuallyExclusive
+		*out = make([][]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	if in.ComputedDefaults != nil {
+		in, out := &in.ComputedDefaults, &out.ComputedDefaults
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.AdditionalPrinterColumns != nil {
 		in, out := &in.AdditionalPrinterColumns, &out.AdditionalPrinterColumns
 		*out = make([]v1.CustomResourceColumnDefinition, len(*in))
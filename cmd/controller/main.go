@@ -17,13 +17,17 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -32,9 +36,11 @@ import (
 
 	xv1alpha1 "github.com/kro-run/kro/api/v1alpha1"
 	kroclient "github.com/kro-run/kro/pkg/client"
+	instancectrl "github.com/kro-run/kro/pkg/controller/instance"
 	resourcegraphdefinitionctrl "github.com/kro-run/kro/pkg/controller/resourcegraphdefinition"
 	"github.com/kro-run/kro/pkg/dynamiccontroller"
 	"github.com/kro-run/kro/pkg/graph"
+	"github.com/kro-run/kro/pkg/metadata"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -66,6 +72,7 @@ func main() {
 		leaderElectionNamespace                     string
 		probeAddr                                   string
 		allowCRDDeletion                            bool
+		lenientSchemaMarkers                        bool
 		resourceGraphDefinitionConcurrentReconciles int
 		dynamicControllerConcurrentReconciles       int
 		// dynamic controller rate limiter parameters
@@ -77,6 +84,17 @@ func main() {
 		resyncPeriod    int
 		queueMaxRetries int
 		shutdownTimeout int
+		// instance reconciler parameters
+		resourceTimeout        time.Duration
+		validateResources      bool
+		adoptResources         bool
+		deletionPropagation    string
+		transientRetryAttempts int
+		transientRetryBackoff  time.Duration
+		allowedServiceAccounts []string
+		conflictRetries        int
+		maxObjectsPerInstance  int
+		instanceFinalizer      string
 		// var dynamicControllerDefaultResyncPeriod int
 		logLevel int
 		qps      float64
@@ -93,6 +111,8 @@ func main() {
 			"leader election. By default it will try to use the namespace of the service account mounted"+
 			" to the controller pod.")
 	flag.BoolVar(&allowCRDDeletion, "allow-crd-deletion", false, "allow kro to delete CRDs")
+	flag.BoolVar(&lenientSchemaMarkers, "lenient-schema-markers", false,
+		"ignore unknown markers in the resource graph definition schemas with a warning, instead of rejecting them")
 	flag.IntVar(&resourceGraphDefinitionConcurrentReconciles,
 		"resource-graph-definition-concurrent-reconciles", 1,
 		"The number of resource graph definition reconciles to run in parallel",
@@ -119,6 +139,42 @@ func main() {
 		"maximum number of retries for an item in the queue will be retried before being dropped")
 	flag.IntVar(&shutdownTimeout, "dynamic-controller-default-shutdown-timeout", 60,
 		"maximum duration to wait for the controller to gracefully shutdown, in seconds")
+	// instance reconciler parameters
+	flag.DurationVar(&resourceTimeout, "instance-resource-timeout", 0,
+		"maximum duration of a single get, create, update or delete call against an instance resource, "+
+			"0 means no timeout")
+	flag.BoolVar(&validateResources, "instance-validate-resources", false,
+		"validate instance resources against their OpenAPI schema before creating or updating them")
+	flag.BoolVar(&adoptResources, "instance-adopt-resources", false,
+		"let instances take over the existing resources managed by another instance, instead of failing")
+	flag.StringVar(&deletionPropagation, "instance-deletion-propagation", "",
+		"propagation policy used to delete the resources of a deleted instance (Orphan, Background or "+
+			"Foreground), unless the resource sets its own, empty means the default policy of the resource kind")
+	flag.IntVar(&transientRetryAttempts, "instance-transient-retry-attempts", 1,
+		"maximum number of attempts of a create or update call against an instance resource failing "+
+			"with a transient error (5xx, timeout, throttling), 1 disables the retries")
+	flag.DurationVar(&transientRetryBackoff, "instance-transient-retry-backoff", 500*time.Millisecond,
+		"delay before the first retry of a call failing with a transient error, doubled after each attempt")
+	flag.Func("instance-allowed-service-accounts",
+		"comma separated list of the service accounts instances can ask kro to impersonate with the "+
+			"kro.run/service-account annotation, as <namespace>/<name> or */<name> for any namespace",
+		func(value string) error {
+			for _, sa := range strings.Split(value, ",") {
+				if sa = strings.TrimSpace(sa); sa != "" {
+					allowedServiceAccounts = append(allowedServiceAccounts, sa)
+				}
+			}
+			return nil
+		})
+	flag.IntVar(&conflictRetries, "instance-conflict-retries", 0,
+		"maximum number of retries of an update against an instance resource failing with a conflict, "+
+			"the resource is read again before each retry, 0 disables the retries")
+	flag.StringVar(&instanceFinalizer, "instance-finalizer", metadata.DefaultFinalizer,
+		"finalizer set on the instances, independent kro deployments managing the same kinds must use "+
+			"different finalizers")
+	flag.IntVar(&maxObjectsPerInstance, "max-objects-per-instance", 0,
+		"maximum number of objects managed for a single instance, an instance exceeding it isn't applied, "+
+			"0 means no limit")
 	// log level flags
 	flag.IntVar(&logLevel, "log-level", 10, "The log level verbosity. 0 is the least verbose, 5 is the most verbose.")
 	// qps and burst
@@ -137,6 +193,19 @@ func main() {
 
 	ctrl.SetLogger(rootLogger)
 
+	if errs := validation.IsQualifiedName(instanceFinalizer); len(errs) > 0 {
+		setupLog.Error(fmt.Errorf("%s", strings.Join(errs, ", ")), "invalid instance finalizer", "finalizer", instanceFinalizer)
+		os.Exit(1)
+	}
+
+	switch metav1.DeletionPropagation(deletionPropagation) {
+	case "", metav1.DeletePropagationOrphan, metav1.DeletePropagationBackground, metav1.DeletePropagationForeground:
+	default:
+		setupLog.Error(fmt.Errorf("must be Orphan, Background or Foreground"), "invalid instance deletion propagation",
+			"propagation", deletionPropagation)
+		os.Exit(1)
+	}
+
 	set, err := kroclient.NewSet(kroclient.Config{
 		QPS:   float32(qps),
 		Burst: burst,
@@ -185,8 +254,13 @@ func main() {
 		BurstLimit:      burstLimit,
 	}, set.Dynamic())
 
+	var builderOpts []graph.BuilderOption
+	if lenientSchemaMarkers {
+		builderOpts = append(builderOpts, graph.WithLenientSchemaMarkers())
+	}
 	resourceGraphDefinitionGraphBuilder, err := graph.NewBuilder(
 		restConfig,
+		builderOpts...,
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create resource graph definition graph builder")
@@ -199,6 +273,25 @@ func main() {
 		dc,
 		resourceGraphDefinitionGraphBuilder,
 		resourceGraphDefinitionConcurrentReconciles,
+		instancectrl.ReconcileConfig{
+			DefaultRequeueDuration:    3 * time.Second,
+			DeletionGraceTimeDuration: 30 * time.Second,
+			DeletionPolicy:            "Delete",
+			ResourceTimeout:           resourceTimeout,
+			ValidateResources:         validateResources,
+			AdoptResources:            adoptResources,
+			DeletionPropagation:       metav1.DeletionPropagation(deletionPropagation),
+			MaxObjectsPerInstance:     maxObjectsPerInstance,
+			Finalizer:                 instanceFinalizer,
+			EventRecorder:             mgr.GetEventRecorderFor("kro"),
+			TransientRetry: instancectrl.TransientRetryConfig{
+				Attempts:        transientRetryAttempts,
+				Backoff:         transientRetryBackoff,
+				Jitter:          0.1,
+				ConflictRetries: conflictRetries,
+			},
+			AllowedServiceAccounts: allowedServiceAccounts,
+		},
 	)
 	if err := rgd.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ResourceGraphDefinition")
@@ -105,6 +105,11 @@ var knownFunctions = []string{
 	"random.seededString",
 	"base64.decode",
 	"base64.encode",
+	"optional.none",
+	"optional.of",
+	"optional.ofNonZeroValue",
+	"toEnvList",
+	"first",
 }
 
 // DefaultInspector creates a new Inspector instance with the given resources and functions.
@@ -260,6 +260,18 @@ func TestInspector_InspectionResults(t *testing.T) {
 				{Name: "map"},
 			},
 		},
+		{
+			name:       "first matching element",
+			resources:  []string{"ingress"},
+			functions:  []string{},
+			expression: `ingress.status.loadBalancer.ingress.firstWhere(i, has(i.hostname)).hostname`,
+			wantResources: []ResourceDependency{
+				{ID: "ingress", Path: "ingress.status.loadBalancer.ingress"},
+			},
+			wantFunctions: []FunctionCall{
+				{Name: "map"},
+			},
+		},
 		{
 			name:          "create message struct",
 			resources:     []string{},
@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 )
 
 var (
@@ -42,9 +43,41 @@ func GoNativeType(v ref.Val) (interface{}, error) {
 	case types.StringType:
 		return v.Value().(string), nil
 	case types.ListType:
-		return v.ConvertToNative(reflect.TypeOf([]interface{}{}))
+		// The elements are converted one by one, lists and maps built by
+		// the expression (e.g with the map macro) would otherwise be left
+		// as CEL values.
+		lister, ok := v.(traits.Lister)
+		if !ok {
+			return v.ConvertToNative(reflect.TypeOf([]interface{}{}))
+		}
+		list := []interface{}{}
+		for it := lister.Iterator(); it.HasNext() == types.True; {
+			element, err := GoNativeType(it.Next())
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, element)
+		}
+		return list, nil
 	case types.MapType:
-		return v.ConvertToNative(reflect.TypeOf(map[string]interface{}{}))
+		mapper, ok := v.(traits.Mapper)
+		if !ok {
+			return v.ConvertToNative(reflect.TypeOf(map[string]interface{}{}))
+		}
+		object := map[string]interface{}{}
+		for it := mapper.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			name, ok := key.Value().(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: map key of type %v", ErrUnsupportedType, key.Type())
+			}
+			value, err := GoNativeType(mapper.Get(key))
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		}
+		return object, nil
 	case types.OptionalType:
 		opt := v.(*types.Optional)
 		if !opt.HasValue() {
@@ -57,6 +57,9 @@ func DefaultEnvironment(options ...EnvOption) (*cel.Env, error) {
 		cel.OptionalTypes(),
 		ext.Encoders(),
 		library.Random(),
+		library.Env(),
+		library.Lists(),
+		library.Quantities(),
 	}
 
 	opts := &envOptions{}
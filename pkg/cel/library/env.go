@@ -0,0 +1,102 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// Env returns a CEL library that provides helpers to build container
+// environment variables.
+//
+// Library functions:
+//
+// toEnvList() converts a map of strings into a list of Kubernetes environment
+// variables, each entry of the map becoming a {name, value} object. The list
+// is sorted by name, so that the same map always produces the same list.
+//
+// Example usage:
+//
+//	toEnvList(schema.spec.env)
+//
+// With schema.spec.env set to {"LOG_LEVEL": "debug", "DB_HOST": "db"}, this
+// returns [{"name": "DB_HOST", "value": "db"}, {"name": "LOG_LEVEL", "value": "debug"}].
+func Env() cel.EnvOption {
+	return cel.Lib(&envLibrary{})
+}
+
+type envLibrary struct{}
+
+func (l *envLibrary) LibraryName() string {
+	return "env"
+}
+
+func (l *envLibrary) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("toEnvList",
+			cel.Overload("toEnvList_map",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.DynType)},
+				cel.ListType(cel.MapType(cel.StringType, cel.StringType)),
+				cel.UnaryBinding(toEnvList),
+			),
+		),
+	}
+}
+
+func (l *envLibrary) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func toEnvList(arg ref.Val) ref.Val {
+	m, ok := arg.(traits.Mapper)
+	if !ok {
+		return types.NewErr("toEnvList argument must be a map")
+	}
+
+	values := make(map[string]string)
+	it := m.Iterator()
+	for it.HasNext() == types.True {
+		key := it.Next()
+		name, ok := key.(types.String)
+		if !ok {
+			return types.NewErr("toEnvList keys must be strings, got %s", key.Type().TypeName())
+		}
+		value, ok := m.Get(key).(types.String)
+		if !ok {
+			return types.NewErr("toEnvList value of %q must be a string, got %s", name, m.Get(key).Type().TypeName())
+		}
+		values[string(name)] = string(value)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envVars := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		envVars = append(envVars, map[string]interface{}{
+			"name":  name,
+			"value": values[name],
+		})
+	}
+	return types.DefaultTypeAdapter.NativeToValue(envVars)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToEnvList(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("schema", cel.AnyType),
+		Env(),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		expr    string
+		env     interface{}
+		want    []interface{}
+		wantErr string
+	}{
+		{
+			name: "entries sorted by name",
+			expr: "toEnvList(schema.spec.env)",
+			env: map[string]interface{}{
+				"ZONE":      "eu-west-1a",
+				"DB_HOST":   "db.default.svc",
+				"LOG_LEVEL": "debug",
+			},
+			want: []interface{}{
+				map[string]interface{}{"name": "DB_HOST", "value": "db.default.svc"},
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+				map[string]interface{}{"name": "ZONE", "value": "eu-west-1a"},
+			},
+		},
+		{
+			name: "empty map",
+			expr: "toEnvList(schema.spec.env)",
+			env:  map[string]interface{}{},
+			want: []interface{}{},
+		},
+		{
+			name: "map literal",
+			expr: "toEnvList({'B': 'b', 'A': 'a'})",
+			want: []interface{}{
+				map[string]interface{}{"name": "A", "value": "a"},
+				map[string]interface{}{"name": "B", "value": "b"},
+			},
+		},
+		{
+			name:    "non string value",
+			expr:    "toEnvList(schema.spec.env)",
+			env:     map[string]interface{}{"REPLICAS": int64(3)},
+			wantErr: `toEnvList value of "REPLICAS" must be a string, got int`,
+		},
+		{
+			name:    "not a map",
+			expr:    "toEnvList(schema.spec.env)",
+			env:     []interface{}{"LOG_LEVEL=debug"},
+			wantErr: "no such overload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expr)
+			require.NoError(t, issues.Err())
+			program, err := env.Program(ast)
+			require.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{
+				"schema": map[string]interface{}{
+					"spec": map[string]interface{}{"env": tt.env},
+				},
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := out.ConvertToNative(reflect.TypeOf([]interface{}{}))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
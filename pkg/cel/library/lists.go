@@ -0,0 +1,92 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/parser"
+)
+
+// Lists returns a CEL library that provides helpers to pick an element of a
+// list without relying on its order. It complements the standard filter, map,
+// exists and all macros.
+//
+// Library functions:
+//
+// first() returns the first element of a list, and an error if the list is
+// empty.
+//
+// firstWhere() is a macro returning the first element of a list matching a
+// predicate, and an error if no element matches. It is a shorthand for
+// first(list.filter(x, predicate)).
+//
+// Example usage:
+//
+//	ingress.status.loadBalancer.ingress.firstWhere(i, has(i.hostname)).hostname
+//
+// This returns the hostname of the first load balancer ingress having one,
+// whatever its position in the list.
+func Lists() cel.EnvOption {
+	return cel.Lib(&listsLibrary{})
+}
+
+type listsLibrary struct{}
+
+func (l *listsLibrary) LibraryName() string {
+	return "lists"
+}
+
+func (l *listsLibrary) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("first",
+			cel.Overload("first_list",
+				[]*cel.Type{cel.ListType(cel.DynType)},
+				cel.DynType,
+				cel.UnaryBinding(first),
+			),
+		),
+		cel.Macros(cel.ReceiverMacro("firstWhere", 2, firstWhere)),
+	}
+}
+
+func (l *listsLibrary) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func first(arg ref.Val) ref.Val {
+	l, ok := arg.(traits.Lister)
+	if !ok {
+		return types.NewErr("first argument must be a list")
+	}
+	if l.Size() == types.IntZero {
+		return types.NewErr("first called on an empty list")
+	}
+	return l.Get(types.IntZero)
+}
+
+// firstWhere expands list.firstWhere(x, predicate) into
+// first(list.filter(x, predicate)).
+func firstWhere(eh parser.ExprHelper, target ast.Expr, args []ast.Expr) (ast.Expr, *common.Error) {
+	filter, err := parser.MakeFilter(eh, target, args)
+	if err != nil {
+		return nil, err
+	}
+	return eh.NewCall("first", filter), nil
+}
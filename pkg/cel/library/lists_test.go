@@ -0,0 +1,123 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLists(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("ingress", cel.AnyType),
+		Lists(),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		expr    string
+		ingress []interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "first element",
+			expr: "first(ingress.status.loadBalancer.ingress).ip",
+			ingress: []interface{}{
+				map[string]interface{}{"ip": "10.0.0.1"},
+				map[string]interface{}{"hostname": "lb.example.com"},
+			},
+			want: "10.0.0.1",
+		},
+		{
+			name: "first matching element",
+			expr: "ingress.status.loadBalancer.ingress.firstWhere(i, has(i.hostname)).hostname",
+			ingress: []interface{}{
+				map[string]interface{}{"ip": "10.0.0.1"},
+				map[string]interface{}{"hostname": "lb.example.com"},
+				map[string]interface{}{"hostname": "other.example.com"},
+			},
+			want: "lb.example.com",
+		},
+		{
+			name: "standard filter macro",
+			expr: "ingress.status.loadBalancer.ingress.filter(i, has(i.hostname) && i.hostname != '')[0].hostname",
+			ingress: []interface{}{
+				map[string]interface{}{"hostname": ""},
+				map[string]interface{}{"hostname": "lb.example.com"},
+			},
+			want: "lb.example.com",
+		},
+		{
+			name: "standard exists macro",
+			expr: "ingress.status.loadBalancer.ingress.exists(i, has(i.ip))",
+			ingress: []interface{}{
+				map[string]interface{}{"hostname": "lb.example.com"},
+			},
+			want: false,
+		},
+		{
+			name:    "empty list",
+			expr:    "first(ingress.status.loadBalancer.ingress)",
+			ingress: []interface{}{},
+			wantErr: "first called on an empty list",
+		},
+		{
+			name: "no matching element",
+			expr: "ingress.status.loadBalancer.ingress.firstWhere(i, has(i.hostname)).hostname",
+			ingress: []interface{}{
+				map[string]interface{}{"ip": "10.0.0.1"},
+			},
+			wantErr: "first called on an empty list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expr)
+			require.NoError(t, issues.Err())
+			program, err := env.Program(ast)
+			require.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{
+				"ingress": map[string]interface{}{
+					"status": map[string]interface{}{
+						"loadBalancer": map[string]interface{}{"ingress": tt.ingress},
+					},
+				},
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.Value())
+		})
+	}
+}
+
+func TestFirstWhereRequiresIdentifier(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("ingress", cel.AnyType), Lists())
+	require.NoError(t, err)
+
+	_, issues := env.Compile("ingress.firstWhere(i.hostname, true)")
+	require.Error(t, issues.Err())
+	assert.Contains(t, issues.Err().Error(), "argument is not an identifier")
+}
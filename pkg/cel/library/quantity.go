@@ -0,0 +1,150 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"strconv"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Quantities returns a CEL library that provides helpers to compute
+// Kubernetes resource quantities, e.g. CPU and memory requests and limits.
+// Quantities are taken as strings or integers, like in the Kubernetes
+// objects, and returned in their canonical string form.
+//
+// Library functions:
+//
+// quantity() parses a quantity and returns its canonical form, and an error
+// if it isn't a valid quantity.
+//
+// addQuantity() returns the sum of two quantities.
+//
+// scaleQuantity() multiplies a quantity by an integer or a double factor.
+//
+// Example usage:
+//
+//	scaleQuantity(schema.spec.memory, 2)
+//
+// This returns "2Gi" for a memory of "1Gi", and "1536Mi" for a factor of 1.5.
+func Quantities() cel.EnvOption {
+	return cel.Lib(&quantitiesLibrary{})
+}
+
+type quantitiesLibrary struct{}
+
+func (l *quantitiesLibrary) LibraryName() string {
+	return "quantities"
+}
+
+func (l *quantitiesLibrary) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("quantity",
+			cel.Overload("quantity_dyn",
+				[]*cel.Type{cel.DynType},
+				cel.StringType,
+				cel.UnaryBinding(canonicalQuantity),
+			),
+		),
+		cel.Function("addQuantity",
+			cel.Overload("addQuantity_dyn_dyn",
+				[]*cel.Type{cel.DynType, cel.DynType},
+				cel.StringType,
+				cel.BinaryBinding(addQuantity),
+			),
+		),
+		cel.Function("scaleQuantity",
+			cel.Overload("scaleQuantity_dyn_int",
+				[]*cel.Type{cel.DynType, cel.IntType},
+				cel.StringType,
+				cel.BinaryBinding(scaleQuantity),
+			),
+			cel.Overload("scaleQuantity_dyn_double",
+				[]*cel.Type{cel.DynType, cel.DoubleType},
+				cel.StringType,
+				cel.BinaryBinding(scaleQuantity),
+			),
+		),
+	}
+}
+
+func (l *quantitiesLibrary) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// parseQuantity parses a quantity given as a string or an integer.
+func parseQuantity(arg ref.Val) (resource.Quantity, ref.Val) {
+	switch v := arg.(type) {
+	case types.String:
+		q, err := resource.ParseQuantity(string(v))
+		if err != nil {
+			return resource.Quantity{}, types.NewErr("invalid quantity %q: %v", string(v), err)
+		}
+		return q, nil
+	case types.Int:
+		return *resource.NewQuantity(int64(v), resource.DecimalSI), nil
+	default:
+		return resource.Quantity{}, types.NewErr("quantity must be a string or an int, got %s", arg.Type().TypeName())
+	}
+}
+
+func canonicalQuantity(arg ref.Val) ref.Val {
+	q, err := parseQuantity(arg)
+	if err != nil {
+		return err
+	}
+	return types.String(q.String())
+}
+
+func addQuantity(lhs, rhs ref.Val) ref.Val {
+	q, err := parseQuantity(lhs)
+	if err != nil {
+		return err
+	}
+	other, err := parseQuantity(rhs)
+	if err != nil {
+		return err
+	}
+	q.Add(other)
+	return types.String(q.String())
+}
+
+func scaleQuantity(arg, factor ref.Val) ref.Val {
+	q, err := parseQuantity(arg)
+	if err != nil {
+		return err
+	}
+
+	var f resource.Quantity
+	switch v := factor.(type) {
+	case types.Int:
+		f = *resource.NewQuantity(int64(v), resource.DecimalSI)
+	case types.Double:
+		parsed, perr := resource.ParseQuantity(strconv.FormatFloat(float64(v), 'f', -1, 64))
+		if perr != nil {
+			return types.NewErr("invalid scale factor %v: %v", float64(v), perr)
+		}
+		f = parsed
+	default:
+		return types.NewErr("scale factor must be an int or a double, got %s", factor.Type().TypeName())
+	}
+
+	dec := q.AsDec()
+	dec.Mul(dec, f.AsDec())
+	return types.String(resource.NewDecimalQuantity(*dec, q.Format).String())
+}
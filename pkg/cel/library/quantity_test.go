@@ -0,0 +1,119 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestQuantities(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("spec", cel.AnyType),
+		Quantities(),
+	)
+	require.NoError(t, err)
+
+	spec := map[string]interface{}{
+		"memory":   "512Mi",
+		"cpu":      "250m",
+		"memGi":    int64(2),
+		"replicas": int64(3),
+		"invalid":  "lots",
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "canonical form",
+			expr: "quantity('1024Mi')",
+			want: "1Gi",
+		},
+		{
+			name: "integer quantity",
+			expr: "quantity(spec.replicas)",
+			want: "3",
+		},
+		{
+			name: "add quantities",
+			expr: "addQuantity(spec.memory, '512Mi')",
+			want: "1Gi",
+		},
+		{
+			name: "add cpu quantities",
+			expr: "addQuantity(spec.cpu, '1')",
+			want: "1250m",
+		},
+		{
+			name: "scale by an integer",
+			expr: "scaleQuantity(spec.memory, spec.replicas)",
+			want: "1536Mi",
+		},
+		{
+			name: "scale by a double",
+			expr: "scaleQuantity('1Gi', 1.5)",
+			want: "1536Mi",
+		},
+		{
+			name: "scale cpu by a double",
+			expr: "scaleQuantity(spec.cpu, 0.5)",
+			want: "125m",
+		},
+		{
+			name: "quantity built from an integer input",
+			expr: "quantity(string(spec.memGi * 1024) + 'Mi')",
+			want: "2Gi",
+		},
+		{
+			name:    "invalid quantity",
+			expr:    "quantity(spec.invalid)",
+			wantErr: `invalid quantity "lots"`,
+		},
+		{
+			name:    "unsupported type",
+			expr:    "quantity(true)",
+			wantErr: "quantity must be a string or an int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expr)
+			require.NoError(t, issues.Err())
+			program, err := env.Program(ast)
+			require.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{"spec": spec})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.Value())
+			// The result is always a valid quantity
+			_, err = resource.ParseQuantity(out.Value().(string))
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -176,7 +176,7 @@ func (w *CRDWrapper) waitForReady(ctx context.Context, name string) error {
 	log := logr.FromContext(ctx)
 	log.Info("Waiting for CRD to become ready", "name", name)
 
-	return wait.PollUntilContextTimeout(ctx, w.pollInterval, w.timeout, true,
+	err := wait.PollUntilContextTimeout(ctx, w.pollInterval, w.timeout, true,
 		func(ctx context.Context) (bool, error) {
 			crd, err := w.Get(ctx, name)
 			if err != nil {
@@ -185,13 +185,21 @@ func (w *CRDWrapper) waitForReady(ctx context.Context, name string) error {
 				}
 				return false, err
 			}
-
-			for _, cond := range crd.Status.Conditions {
-				if cond.Type == v1.Established && cond.Status == v1.ConditionTrue {
-					return true, nil
-				}
-			}
-
-			return false, nil
+			return IsEstablished(crd), nil
 		})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("CRD %s is not established yet: %w", name, err)
+	}
+	return err
+}
+
+// IsEstablished returns true if the CRD reports the Established condition,
+// meaning the API server is serving its resources.
+func IsEstablished(crd *v1.CustomResourceDefinition) bool {
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == v1.Established && cond.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
 }
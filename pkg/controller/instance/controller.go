@@ -24,8 +24,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/kro-run/kro/api/v1alpha1"
@@ -34,6 +36,8 @@ import (
 	"github.com/kro-run/kro/pkg/metadata"
 )
 
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 // ReconcileConfig holds configuration parameters for the reconciliation process.
 // It allows the customization of various aspects of the controller's behavior.
 type ReconcileConfig struct {
@@ -48,6 +52,97 @@ type ReconcileConfig struct {
 	// TODO(a-hilaly): need to define think the different deletion policies we need to
 	// support.
 	DeletionPolicy string
+	// ResourceTimeout bounds each get, create, update and delete call made
+	// against a sub-resource, so that a single hanging call (e.g. a webhook that never
+	// responds) doesn't stall the whole reconciliation. Zero means no timeout.
+	ResourceTimeout time.Duration
+	// ValidateResources enables a local validation of the resolved resources
+	// against their OpenAPI schema before they are created or updated. It
+	// catches obvious template errors (unknown fields, wrong types...) with
+	// the path of the offending field.
+	ValidateResources bool
+	// TransientRetry configures the retries, within the same reconciliation,
+	// of the create and update calls failing with a transient error.
+	TransientRetry TransientRetryConfig
+	// ImageRewrite, if set, rewrites the container images of the workloads
+	// (Pods, Deployments, StatefulSets, CronJobs...) before they are created
+	// or updated, e.g. to pull them from a private registry mirror.
+	ImageRewrite func(image string) string
+	// AllowedServiceAccounts lists the service accounts an instance can ask
+	// kro to impersonate with the ServiceAccountAnnotation. Entries are
+	// either "<namespace>/<name>" or "*/<name>" to allow a service account
+	// name in any namespace. Empty means the annotation is rejected.
+	AllowedServiceAccounts []string
+	// MaxObjectsPerInstance caps the number of objects managed for a single
+	// instance. An instance exceeding it isn't reconciled at all, to guard
+	// the API server against a runaway graph. Zero means no limit.
+	MaxObjectsPerInstance int
+	// Finalizer is the finalizer set on the instances, so that independent kro
+	// deployments managing the same kinds don't release each other's
+	// instances. Defaults to metadata.DefaultFinalizer.
+	Finalizer string
+	// EventRecorder, if set, records an event when an instance becomes ready
+	// and when it stops being ready, and for each of its resources created,
+	// updated or deleted.
+	EventRecorder record.EventRecorder
+	// PolicyCheck, if set, is called with every resource about to be created
+	// or updated, once it is resolved and mutated, e.g. to run it through a
+	// policy engine such as OPA. A non nil error blocks the apply of the
+	// resource, see PolicyFailMode.
+	PolicyCheck func(ctx context.Context, obj *unstructured.Unstructured) error
+	// PolicyFailMode decides what happens when PolicyCheck rejects a resource.
+	// Defaults to PolicyFailModeSkip.
+	PolicyFailMode PolicyFailMode
+	// AdoptResources lets an instance take over the existing resources that
+	// are labeled as managed by another instance. By default such resources
+	// aren't updated and the reconciliation fails, so that two instances
+	// don't silently fight over the same object.
+	AdoptResources bool
+	// DeletionPropagation is the propagation policy used to delete the
+	// resources of a deleted instance, unless the resource sets its own.
+	// Empty means the default policy of the resource kind.
+	DeletionPropagation metav1.DeletionPropagation
+}
+
+// PolicyFailMode is the behavior of the reconciliation when a resource is
+// rejected by the ReconcileConfig.PolicyCheck.
+type PolicyFailMode string
+
+const (
+	// PolicyFailModeSkip doesn't apply the rejected resource, nor the resources
+	// depending on it, but carries on with the other ones.
+	PolicyFailModeSkip PolicyFailMode = "Skip"
+	// PolicyFailModeAbort stops the reconciliation at the rejected resource,
+	// none of the following resources are applied.
+	PolicyFailModeAbort PolicyFailMode = "Abort"
+)
+
+// finalizer returns the finalizer set on the instances.
+func (c ReconcileConfig) finalizer() string {
+	if c.Finalizer == "" {
+		return metadata.DefaultFinalizer
+	}
+	return c.Finalizer
+}
+
+// TransientRetryConfig holds the retry parameters of the calls made against
+// the sub-resources that fail with a transient error (internal server errors,
+// server timeouts, throttling...). Retrying them in place avoids failing the
+// whole reconciliation and waiting for the next requeue.
+type TransientRetryConfig struct {
+	// Attempts is the maximum number of attempts of a call, including the
+	// first one. Values lower than 2 disable the retries.
+	Attempts int
+	// Backoff is the delay before the first retry, it doubles after each
+	// attempt.
+	Backoff time.Duration
+	// Jitter adds a random delay of up to Jitter*delay to each retry.
+	Jitter float64
+	// ConflictRetries is the maximum number of retries of an update failing
+	// with a conflict, because the resource was modified since it was read.
+	// The resource is read again before each retry, with the same backoff as
+	// the transient errors. Zero disables the retries.
+	ConflictRetries int
 }
 
 // Controller manages the reconciliation of a single instance of a ResourceGraphDefinition,
@@ -81,6 +176,9 @@ type Controller struct {
 	// managing instances for.
 	// TODO: use a read-only interface for the ResourceGraphDefinition
 	rgd *graph.Graph
+	// rgdGeneration is the generation of the ResourceGraphDefinition the
+	// graph was built from.
+	rgdGeneration int64
 	// instanceLabeler is responsible for applying consistent labels
 	// to resources managed by this controller.
 	instanceLabeler metadata.Labeler
@@ -89,6 +187,12 @@ type Controller struct {
 	reconcileConfig ReconcileConfig
 	// defaultServiceAccounts is a map of service accounts to use for controller impersonation.
 	defaultServiceAccounts map[string]string
+	// propagation selects the instance labels and annotations to copy onto
+	// the sub-resources.
+	propagation *v1alpha1.Propagation
+	// observedResources remembers the resources observed by the previous
+	// reconciliations, to detect the ones deleted outside of kro.
+	observedResources *observedResources
 }
 
 // NewController creates a new Controller instance.
@@ -97,8 +201,10 @@ func NewController(
 	reconcileConfig ReconcileConfig,
 	gvr schema.GroupVersionResource,
 	rgd *graph.Graph,
+	rgdGeneration int64,
 	clientSet kroclient.SetInterface,
 	defaultServiceAccounts map[string]string,
+	propagation *v1alpha1.Propagation,
 	instanceLabeler metadata.Labeler,
 ) *Controller {
 	return &Controller{
@@ -106,9 +212,12 @@ func NewController(
 		gvr:                    gvr,
 		clientSet:              clientSet,
 		rgd:                    rgd,
+		rgdGeneration:          rgdGeneration,
 		instanceLabeler:        instanceLabeler,
 		reconcileConfig:        reconcileConfig,
 		defaultServiceAccounts: defaultServiceAccounts,
+		propagation:            propagation,
+		observedResources:      newObservedResources(),
 	}
 }
 
@@ -118,7 +227,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 
 	log := c.log.WithValues("namespace", namespace, "name", name)
 
-	instance, err := c.clientSet.Dynamic().Resource(c.gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	instance, err := c.instanceClient(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Instance not found, it may have been deleted")
@@ -127,6 +236,18 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 		log.Error(err, "Failed to get instance")
 		return nil
 	}
+	log = withInstanceValues(log, instance)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	expiry, expires, err := metadata.GetExpiry(instance)
+	if err != nil {
+		log.Error(err, "Ignoring the instance TTL")
+	}
+	expires = expires && instance.GetDeletionTimestamp() == nil
+	if expires && !time.Now().Before(expiry) {
+		log.Info("Deleting expired instance", "expiry", expiry)
+		return deleteExpiredInstance(ctx, c.instanceClient(namespace), instance)
+	}
 
 	// This is one of the main reasons why we're splitting the controller into
 	// two parts. The instantiator is responsible for creating a new runtime
@@ -143,38 +264,91 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 		return fmt.Errorf("failed to create instance sub-resources labeler: %w", err)
 	}
 
+	subResourceMetadata, err := metadata.NewSubResourceMetadata(instance, instanceSubResourcesLabeler, c.propagation)
+	if err != nil {
+		return err
+	}
+
 	// If possible, use a service account to create the execution client
 	// TODO(a-hilaly): client caching
-	executionClient, err := c.getExecutionClient(namespace)
+	var executionClient dynamic.Interface
+	if sa := metadata.GetServiceAccount(instance); sa != "" {
+		executionClient, err = c.getInstanceExecutionClient(namespace, sa)
+	} else {
+		executionClient, err = c.getExecutionClient(namespace)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create execution client: %w", err)
 	}
 
 	instanceGraphReconciler := &instanceGraphReconciler{
-		log:                         log,
-		gvr:                         c.gvr,
-		client:                      executionClient,
-		runtime:                     rgRuntime,
-		instanceLabeler:             c.instanceLabeler,
-		instanceSubResourcesLabeler: instanceSubResourcesLabeler,
-		reconcileConfig:             c.reconcileConfig,
+		log:                 log,
+		gvr:                 c.gvr,
+		client:              executionClient,
+		runtime:             rgRuntime,
+		instanceLabeler:     c.instanceLabeler,
+		subResourceMetadata: subResourceMetadata,
+		observedResources:   c.observedResources,
+		reconcileConfig:     c.reconcileConfig,
+		readyGate:           newResourceSet(c.rgd.ReadyGate),
+		rgdGeneration:       c.rgdGeneration,
 		// Fresh instance state at each reconciliation loop.
 		state: newInstanceState(),
 	}
-	return instanceGraphReconciler.reconcile(ctx)
+	if c.rgd.ReadyExpression != "" && instanceGraphReconciler.readyGate == nil {
+		// The ready expression decides the instance readiness, the resources
+		// don't gate it on their own.
+		instanceGraphReconciler.readyGate = map[string]struct{}{}
+	}
+	err = instanceGraphReconciler.reconcile(ctx)
+	if expires {
+		return requeueBeforeExpiry(err, time.Until(expiry))
+	}
+	return err
+}
+
+// withInstanceValues annotates the logger with the instance uid, on top of its
+// namespace and name.
+func withInstanceValues(log logr.Logger, instance metav1.Object) logr.Logger {
+	return log.WithValues("uid", instance.GetUID())
+}
+
+// newResourceSet returns the set of the given resource IDs, or nil if there
+// are none.
+func newResourceSet(ids []string) map[string]struct{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
 }
 
-// getNamespaceName extracts the namespace and name from the request.
+// getNamespaceName extracts the namespace and name from the request. The keys
+// of cluster scoped instances have no namespace, an empty namespace is
+// returned for them.
 func getNamespaceName(req ctrl.Request) (string, string) {
-	parts := strings.Split(req.Name, "/")
-	name := parts[len(parts)-1]
-	namespace := parts[0]
+	namespace, name, ok := strings.Cut(req.Name, "/")
+	if !ok {
+		return "", req.Name
+	}
 	if namespace == "" {
 		namespace = metav1.NamespaceDefault
 	}
 	return namespace, name
 }
 
+// instanceClient returns the client of the instances, scoped to the given
+// namespace unless the instances are cluster scoped.
+func (c *Controller) instanceClient(namespace string) dynamic.ResourceInterface {
+	if !c.rgd.Instance.IsNamespaced() {
+		return c.clientSet.Dynamic().Resource(c.gvr)
+	}
+	return c.clientSet.Dynamic().Resource(c.gvr).Namespace(namespace)
+}
+
 // errorCategory helps classify different types of impersonation errors
 type errorCategory string
 
@@ -189,6 +363,9 @@ const (
 // If the instance is created in a namespace of which a service account is specified,
 // the execution client will be created using the service account. If no service account
 // is specified for the namespace, the default client will be used.
+//
+// Cluster scoped instances have no namespace: only the default service account
+// applies to them, and it must be referenced as "<namespace>/<name>".
 func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, error) {
 	// if no service accounts are specified, use the default client
 	if len(c.defaultServiceAccounts) == 0 {
@@ -201,7 +378,7 @@ func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, er
 
 	// Check for namespace specific service account
 	if sa, ok := c.defaultServiceAccounts[namespace]; ok {
-		userName, err := getServiceAccountUserName(namespace, sa)
+		userName, err := getServiceAccountUserName(serviceAccountReference(namespace, sa))
 		if err != nil {
 			c.handleImpersonateError(namespace, sa, err)
 			return nil, fmt.Errorf("invalid service account configuration: %w", err)
@@ -219,7 +396,7 @@ func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, er
 
 	// Check for default service account (marked by "*")
 	if defaultSA, ok := c.defaultServiceAccounts[v1alpha1.DefaultServiceAccountKey]; ok {
-		userName, err := getServiceAccountUserName(namespace, defaultSA)
+		userName, err := getServiceAccountUserName(serviceAccountReference(namespace, defaultSA))
 		if err != nil {
 			c.handleImpersonateError(namespace, defaultSA, err)
 			return nil, fmt.Errorf("invalid default service account configuration: %w", err)
@@ -240,6 +417,58 @@ func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, er
 	return c.clientSet.Dynamic(), nil
 }
 
+// getInstanceExecutionClient returns an execution client impersonating the
+// service account requested by an instance through the ServiceAccountAnnotation.
+// The service account lives in the namespace of the instance, and must be
+// listed in the AllowedServiceAccounts of the reconcile configuration: an
+// instance can't escalate its privileges to any service account of its
+// namespace. Cluster scoped instances have no namespace, they must reference
+// the service account as "<namespace>/<name>".
+func (c *Controller) getInstanceExecutionClient(namespace, ref string) (dynamic.Interface, error) {
+	saNamespace, sa := serviceAccountReference(namespace, ref)
+	timer := prometheus.NewTimer(impersonationDuration.WithLabelValues(saNamespace, sa))
+	defer timer.ObserveDuration()
+
+	if namespace != "" && saNamespace != namespace {
+		recordImpersonateError(namespace, ref, errorInvalidSA)
+		return nil, fmt.Errorf("service account %s must live in the namespace %s of the instance", ref, namespace)
+	}
+	if !isServiceAccountAllowed(c.reconcileConfig.AllowedServiceAccounts, saNamespace, sa) {
+		recordImpersonateError(saNamespace, sa, errorInvalidSA)
+		return nil, fmt.Errorf("service account %s/%s is not allowed to be impersonated", saNamespace, sa)
+	}
+
+	userName, err := getServiceAccountUserName(saNamespace, sa)
+	if err != nil {
+		c.handleImpersonateError(saNamespace, sa, err)
+		return nil, fmt.Errorf("invalid instance service account: %w", err)
+	}
+
+	pivotedClient, err := c.clientSet.WithImpersonation(userName)
+	if err != nil {
+		c.handleImpersonateError(saNamespace, sa, err)
+		return nil, fmt.Errorf("failed to create impersonated client with instance SA: %w", err)
+	}
+
+	impersonationTotal.WithLabelValues(saNamespace, sa, "success").Inc()
+	return pivotedClient.Dynamic(), nil
+}
+
+// isServiceAccountAllowed returns true if the service account of the given
+// namespace matches one of the "<namespace>/<name>" or "*/<name>" entries.
+func isServiceAccountAllowed(allowed []string, namespace, sa string) bool {
+	for _, entry := range allowed {
+		ns, name, ok := strings.Cut(strings.TrimSpace(entry), "/")
+		if !ok || name != sa {
+			continue
+		}
+		if ns == namespace || ns == v1alpha1.DefaultServiceAccountKey {
+			return true
+		}
+	}
+	return false
+}
+
 // handleImpersonateError logs the error and records the error in the metrics
 func (c *Controller) handleImpersonateError(namespace, sa string, err error) {
 	var category errorCategory
@@ -261,10 +490,23 @@ func (c *Controller) handleImpersonateError(namespace, sa string, err error) {
 	)
 }
 
+// serviceAccountReference splits a service account reference, either
+// "<namespace>/<name>" or a bare "<name>" living in the given namespace, into
+// the namespace and the name of the service account.
+func serviceAccountReference(namespace, ref string) (string, string) {
+	if ns, name, ok := strings.Cut(ref, "/"); ok {
+		return ns, name
+	}
+	return namespace, ref
+}
+
 // getServiceAccountUserName builds the impersonate service account user name.
 // The format of the user name is "system:serviceaccount:<namespace>:<serviceaccount>"
 func getServiceAccountUserName(namespace, serviceAccount string) (string, error) {
-	if namespace == "" || serviceAccount == "" {
+	if namespace == "" {
+		return "", fmt.Errorf("service account %s has no namespace, cluster scoped instances must reference it as <namespace>/<name>", serviceAccount)
+	}
+	if serviceAccount == "" {
 		return "", fmt.Errorf("namespace and service account must be provided")
 	}
 	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount), nil
@@ -16,7 +16,11 @@ package instance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -24,9 +28,12 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/kro-run/kro/pkg/controller/instance/delta"
+	graphschema "github.com/kro-run/kro/pkg/graph/schema"
 	"github.com/kro-run/kro/pkg/metadata"
 	"github.com/kro-run/kro/pkg/requeue"
 	"github.com/kro-run/kro/pkg/runtime"
@@ -44,6 +51,7 @@ const (
 	ResourceStatePendingDeletion     = "PENDING_DELETION"
 	ResourceStateWaitingForReadiness = "WAITING_FOR_READINESS"
 	ResourceStateUpdating            = "UPDATING"
+	ResourceStateProtected           = "PROTECTED"
 )
 
 // instanceGraphReconciler is responsible for reconciling a single instance and
@@ -62,14 +70,46 @@ type instanceGraphReconciler struct {
 	runtime runtime.Interface
 	// instanceLabeler is responsible for applying labels to the instance object
 	instanceLabeler metadata.Labeler
-	// instanceSubResourcesLabeler is responsible for applying labels to the
-	// sub resources.
-	instanceSubResourcesLabeler metadata.Labeler
+	// subResourceMetadata holds the labels and annotations applied to the sub
+	// resources.
+	subResourceMetadata metadata.SubResourceMetadata
 	// reconcileConfig holds the configuration parameters for the reconciliation
 	// process.
 	reconcileConfig ReconcileConfig
 	// state holds the current state of the instance and its sub-resources.
 	state *InstanceState
+	// applyOnly, when set, holds the IDs of the only resources that can be
+	// created or updated during this reconciliation. The other resources are
+	// only observed. It is populated from the metadata.ApplyOnlyAnnotation.
+	applyOnly map[string]struct{}
+	// reconcileInterval, when set, overrides the default requeue duration of
+	// the instance. It is populated from the metadata.ReconcileIntervalAnnotation.
+	reconcileInterval time.Duration
+	// suspended is true when the workloads of the instance must be kept
+	// suspended. It is populated from the metadata.SuspendAnnotation.
+	suspended bool
+	// readyGate, when set, holds the IDs of the only resources whose readiness
+	// gates the instance readiness. It is populated from the resource graph
+	// definition readyGate field.
+	readyGate map[string]struct{}
+	// ungatedNotReady holds the IDs of the resources outside the ready gate
+	// that are not ready yet (or depend on such resources) during this
+	// reconciliation.
+	ungatedNotReady map[string]struct{}
+	// rgdGeneration is the generation of the ResourceGraphDefinition the
+	// instance is reconciled against.
+	rgdGeneration int64
+	// policyRejected holds the IDs of the resources rejected by the policy
+	// check during this reconciliation.
+	policyRejected []string
+	// observedResources remembers the resources observed by the previous
+	// reconciliations, shared by all the instances of the controller.
+	observedResources *observedResources
+	// manifests holds the resolved manifests of the resources created or
+	// compared with their observed state during this reconciliation, by
+	// resource ID. They are stored when the instance has the
+	// metadata.StoreManifestsAnnotation.
+	manifests map[string]*unstructured.Unstructured
 }
 
 // reconcile performs the reconciliation of the instance and its sub-resources.
@@ -78,6 +118,7 @@ type instanceGraphReconciler struct {
 func (igr *instanceGraphReconciler) reconcile(ctx context.Context) error {
 	instance := igr.runtime.GetInstance()
 	igr.state = newInstanceState()
+	igr.reconcileInterval = igr.instanceReconcileInterval(instance)
 
 	// Handle instance deletion if marked for deletion
 	if !instance.GetDeletionTimestamp().IsZero() {
@@ -85,7 +126,13 @@ func (igr *instanceGraphReconciler) reconcile(ctx context.Context) error {
 		return igr.handleReconciliation(ctx, igr.handleInstanceDeletion)
 	}
 
-	return igr.handleReconciliation(ctx, igr.reconcileInstance)
+	err := igr.handleReconciliation(ctx, igr.reconcileInstance)
+	if metadata.IsStoringManifests(instance) {
+		if err := igr.storeManifests(ctx, instance); err != nil {
+			igr.log.Error(err, "Failed to store the resolved manifests")
+		}
+	}
+	return err
 }
 
 // handleReconciliation provides a common wrapper for reconciliation operations,
@@ -102,28 +149,66 @@ func (igr *instanceGraphReconciler) handleReconciliation(ctx context.Context, re
 			if !apierrors.IsNotFound(err) {
 				igr.log.Error(err, "Failed to patch instance status")
 			}
+			return
 		}
+		igr.recordReadinessTransition()
 	}()
 
 	igr.state.ReconcileErr = reconcileFunc(ctx)
 	return igr.state.ReconcileErr
 }
 
+// instanceReconcileInterval returns the reconcile interval set on the
+// instance, an invalid interval is logged and ignored.
+func (igr *instanceGraphReconciler) instanceReconcileInterval(instance *unstructured.Unstructured) time.Duration {
+	interval, err := metadata.GetReconcileInterval(instance)
+	if err != nil {
+		igr.log.Error(err, "Ignoring the reconcile interval of the instance")
+	}
+	return interval
+}
+
 // reconcileInstance handles the reconciliation of an active instance
 func (igr *instanceGraphReconciler) reconcileInstance(ctx context.Context) error {
 	instance := igr.runtime.GetInstance()
 
+	// Check the size of the graph before anything is applied.
+	if err := igr.checkObjectCount(); err != nil {
+		return err
+	}
+
 	// Set managed state and handle instance labels
 	if err := igr.setupInstance(ctx, instance); err != nil {
 		return fmt.Errorf("failed to setup instance: %w", err)
 	}
 
+	igr.applyOnly = metadata.GetApplyOnlyResources(instance)
+	if igr.applyOnly != nil {
+		igr.log.Info("Restricting reconciliation to a subset of resources",
+			"annotation", metadata.ApplyOnlyAnnotation,
+			"resources", igr.applyOnly,
+		)
+		igr.state.UnknownApplyOnly = igr.unknownApplyOnlyResources()
+		if len(igr.state.UnknownApplyOnly) > 0 {
+			igr.log.Info("Ignoring unknown resources of the apply-only annotation",
+				"annotation", metadata.ApplyOnlyAnnotation,
+				"resources", igr.state.UnknownApplyOnly,
+			)
+		}
+	}
+
+	igr.suspended = metadata.IsSuspended(instance)
+	if igr.suspended {
+		igr.log.V(1).Info("Keeping the instance workloads suspended", "annotation", metadata.SuspendAnnotation)
+	}
+
 	// Initialize resource states
 	for _, resourceID := range igr.runtime.TopologicalOrder() {
 		igr.state.ResourceStates[resourceID] = &ResourceState{State: ResourceStatePending}
 	}
 
 	// Reconcile resources in topological order
+	igr.ungatedNotReady = make(map[string]struct{})
 	for _, resourceID := range igr.runtime.TopologicalOrder() {
 		if err := igr.reconcileResource(ctx, resourceID); err != nil {
 			return err
@@ -135,6 +220,84 @@ func (igr *instanceGraphReconciler) reconcileInstance(ctx context.Context) error
 		}
 	}
 
+	if len(igr.policyRejected) > 0 {
+		return &policyRejectedError{resourceIDs: igr.policyRejected}
+	}
+
+	if ready, reason, err := igr.runtime.IsInstanceReady(); err != nil || !ready {
+		igr.log.V(1).Info("Instance not ready", "reason", reason, "error", err)
+		if err != nil {
+			return igr.delayedRequeue(fmt.Errorf("instance not ready: %w", err))
+		}
+		return igr.delayedRequeue(fmt.Errorf("instance not ready: %s", reason))
+	}
+
+	if len(igr.ungatedNotReady) > 0 {
+		// The resources gating the instance readiness are all ready, but we
+		// still need to requeue to keep track of the other ones.
+		igr.state.State = InstanceStateActive
+		ids := make([]string, 0, len(igr.ungatedNotReady))
+		for id := range igr.ungatedNotReady {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return igr.delayedRequeue(fmt.Errorf("resources outside the ready gate are not ready: %s", strings.Join(ids, ", ")))
+	}
+
+	return nil
+}
+
+// externalRefNotFoundError is returned when the object an external reference
+// points to doesn't exist.
+type externalRefNotFoundError struct {
+	resourceID string
+	gvk        schema.GroupVersionKind
+	namespace  string
+	name       string
+	err        error
+}
+
+func (e *externalRefNotFoundError) Error() string {
+	name := e.name
+	if e.namespace != "" {
+		name = e.namespace + "/" + name
+	}
+	return fmt.Sprintf("external reference %s not found: %s %s %s",
+		e.resourceID, e.gvk.GroupVersion().String(), e.gvk.Kind, name)
+}
+
+func (e *externalRefNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// tooManyObjectsError is returned when an instance manages more objects than
+// allowed by ReconcileConfig.MaxObjectsPerInstance.
+type tooManyObjectsError struct {
+	count int
+	limit int
+}
+
+func (e *tooManyObjectsError) Error() string {
+	return fmt.Sprintf("instance manages %d objects, more than the limit of %d: nothing was applied", e.count, e.limit)
+}
+
+// checkObjectCount returns a tooManyObjectsError if the instance manages more
+// objects than allowed. The external references aren't managed by kro, they
+// don't count.
+func (igr *instanceGraphReconciler) checkObjectCount() error {
+	limit := igr.reconcileConfig.MaxObjectsPerInstance
+	if limit <= 0 {
+		return nil
+	}
+	count := 0
+	for _, resourceID := range igr.runtime.TopologicalOrder() {
+		if !igr.runtime.ResourceDescriptor(resourceID).IsExternalRef() {
+			count++
+		}
+	}
+	if count > limit {
+		return &tooManyObjectsError{count: count, limit: limit}
+	}
 	return nil
 }
 
@@ -153,7 +316,7 @@ func (igr *instanceGraphReconciler) setupInstance(ctx context.Context, instance
 
 // reconcileResource handles the reconciliation of a single resource within the instance
 func (igr *instanceGraphReconciler) reconcileResource(ctx context.Context, resourceID string) error {
-	log := igr.log.WithValues("resourceID", resourceID)
+	log := igr.resourceLogger(resourceID)
 	resourceState := &ResourceState{State: ResourceStateInProgress}
 	igr.state.ResourceStates[resourceID] = resourceState
 
@@ -165,12 +328,31 @@ func (igr *instanceGraphReconciler) reconcileResource(ctx context.Context, resou
 		return nil
 	}
 
+	// Resources depending on a resource outside the ready gate that isn't
+	// ready yet have to wait for it.
+	if dependency, ok := igr.waitingOnUngatedResource(resourceID); ok {
+		resourceState.State = ResourceStateWaitingForReadiness
+		resourceState.Err = fmt.Errorf("dependency %s is not ready", dependency)
+		if igr.gatesReadiness(resourceID) {
+			return igr.delayedRequeue(resourceState.Err)
+		}
+		log.V(1).Info("Resource waiting on a dependency outside the ready gate", "dependency", dependency)
+		igr.ungatedNotReady[resourceID] = struct{}{}
+		return nil
+	}
+
 	// Get and validate resource state
 	resource, state := igr.runtime.GetResource(resourceID)
 	if state != runtime.ResourceStateResolved {
 		return igr.delayedRequeue(fmt.Errorf("resource %s not resolved: state=%v", resourceID, state))
 	}
 
+	if err := igr.validateResource(resourceID, resource); err != nil {
+		resourceState.State = ResourceStateError
+		resourceState.Err = err
+		return err
+	}
+
 	// Handle resource reconciliation
 	return igr.handleResourceReconciliation(ctx, resourceID, resource, resourceState)
 }
@@ -183,21 +365,39 @@ func (igr *instanceGraphReconciler) handleResourceReconciliation(
 	resource *unstructured.Unstructured,
 	resourceState *ResourceState,
 ) error {
-	log := igr.log.WithValues("resourceID", resourceID)
+	log := igr.resourceLogger(resourceID)
 
 	// Get resource client and namespace
 	rc := igr.getResourceClient(resourceID)
 
 	// Check if resource exists
-	observed, err := rc.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	getCtx, cancel := igr.resourceContext(ctx)
+	observed, err := rc.Get(getCtx, resource.GetName(), metav1.GetOptions{})
+	cancel()
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// For read-only resources, we don't create
 			if igr.runtime.ResourceDescriptor(resourceID).IsExternalRef() {
 				resourceState.State = "WAITING_FOR_EXTERNAL_RESOURCE"
-				resourceState.Err = fmt.Errorf("external resource not found: %w", err)
+				resourceState.Err = &externalRefNotFoundError{
+					resourceID: resourceID,
+					gvk:        resource.GroupVersionKind(),
+					namespace:  resource.GetNamespace(),
+					name:       resource.GetName(),
+					err:        err,
+				}
 				return igr.delayedRequeue(resourceState.Err)
 			}
+			if !igr.shouldApply(resourceID) {
+				log.V(1).Info("Skipping resource creation, resource is not part of the apply-only set")
+				resourceState.State = ResourceStateSkipped
+				igr.runtime.IgnoreResource(resourceID)
+				return nil
+			}
+			if igr.observedResources.observed(igr.runtime.GetInstance(), resourceID, resource) {
+				log.Info("Resource was deleted outside of kro, recreating it")
+				igr.state.Recreated = append(igr.state.Recreated, resourceID)
+			}
 			return igr.handleResourceCreation(ctx, rc, resource, resourceID, resourceState)
 		}
 		resourceState.State = ResourceStateError
@@ -207,12 +407,17 @@ func (igr *instanceGraphReconciler) handleResourceReconciliation(
 
 	// Update runtime with observed state
 	igr.runtime.SetResource(resourceID, observed)
+	igr.observedResources.observe(igr.runtime.GetInstance(), resourceID, observed)
 
 	// Check resource readiness
 	if ready, reason, err := igr.runtime.IsResourceReady(resourceID); err != nil || !ready {
 		log.V(1).Info("Resource not ready", "reason", reason, "error", err)
 		resourceState.State = ResourceStateWaitingForReadiness
 		resourceState.Err = fmt.Errorf("resource not ready: %s: %w", reason, err)
+		if !igr.gatesReadiness(resourceID) {
+			igr.ungatedNotReady[resourceID] = struct{}{}
+			return nil
+		}
 		return igr.delayedRequeue(resourceState.Err)
 	}
 
@@ -223,9 +428,97 @@ func (igr *instanceGraphReconciler) handleResourceReconciliation(
 		return nil
 	}
 
+	// Resources reconciled once are left alone after their creation
+	if igr.runtime.ResourceDescriptor(resourceID).ReconcileOnce() {
+		log.V(1).Info("Skipping resource update, resource is only reconciled once")
+		return nil
+	}
+
+	if !igr.shouldApply(resourceID) {
+		log.V(1).Info("Skipping resource update, resource is not part of the apply-only set")
+		return nil
+	}
+
 	return igr.updateResource(ctx, rc, resource, observed, resourceID, resourceState)
 }
 
+// unknownApplyOnlyResources returns the sorted IDs listed in the
+// ApplyOnlyAnnotation that are not resources of the graph, e.g. misspelled
+// ones. They are ignored, but reported on the status of the instance.
+func (igr *instanceGraphReconciler) unknownApplyOnlyResources() []string {
+	known := make(map[string]struct{})
+	for _, resourceID := range igr.runtime.TopologicalOrder() {
+		known[resourceID] = struct{}{}
+	}
+	var unknown []string
+	for resourceID := range igr.applyOnly {
+		if _, ok := known[resourceID]; !ok {
+			unknown = append(unknown, resourceID)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// shouldApply returns true if the resource can be created or updated during
+// this reconciliation.
+func (igr *instanceGraphReconciler) shouldApply(resourceID string) bool {
+	if igr.applyOnly == nil {
+		return true
+	}
+	_, ok := igr.applyOnly[resourceID]
+	return ok
+}
+
+// validateResource validates the resolved resource against its OpenAPI schema,
+// if enabled. External references are not validated, they are never applied.
+func (igr *instanceGraphReconciler) validateResource(resourceID string, resource *unstructured.Unstructured) error {
+	if !igr.reconcileConfig.ValidateResources {
+		return nil
+	}
+	descriptor := igr.runtime.ResourceDescriptor(resourceID)
+	if descriptor.IsExternalRef() {
+		return nil
+	}
+	if err := graphschema.ValidateObject(descriptor.GetSchema(), resource.Object); err != nil {
+		return fmt.Errorf("resource %s is invalid: %w", resourceID, err)
+	}
+	return nil
+}
+
+// gatesReadiness returns true if the readiness of the resource gates the
+// readiness of the instance.
+func (igr *instanceGraphReconciler) gatesReadiness(resourceID string) bool {
+	if igr.readyGate == nil {
+		return true
+	}
+	_, ok := igr.readyGate[resourceID]
+	return ok
+}
+
+// waitingOnUngatedResource returns the first dependency of the resource that
+// is outside the ready gate and not ready yet, if any.
+func (igr *instanceGraphReconciler) waitingOnUngatedResource(resourceID string) (string, bool) {
+	for _, dependency := range igr.runtime.ResourceDescriptor(resourceID).GetDependencies() {
+		if _, ok := igr.ungatedNotReady[dependency]; ok {
+			return dependency, true
+		}
+	}
+	return "", false
+}
+
+// resourceLogger returns the reconciler logger annotated with the resource
+// id and GVR, so that every log line can be correlated to the instance and
+// resource being processed.
+func (igr *instanceGraphReconciler) resourceLogger(resourceID string) logr.Logger {
+	return withResourceValues(igr.log, resourceID, igr.runtime.ResourceDescriptor(resourceID).GetGroupVersionResource())
+}
+
+// withResourceValues annotates the logger with the resource id and GVR.
+func withResourceValues(log logr.Logger, resourceID string, gvr schema.GroupVersionResource) logr.Logger {
+	return log.WithValues("resourceID", resourceID, "gvr", gvr.String())
+}
+
 // getResourceClient returns the appropriate dynamic client and namespace for a resource
 func (igr *instanceGraphReconciler) getResourceClient(resourceID string) dynamic.ResourceInterface {
 	descriptor := igr.runtime.ResourceDescriptor(resourceID)
@@ -246,20 +539,78 @@ func (igr *instanceGraphReconciler) handleResourceCreation(
 	resourceID string,
 	resourceState *ResourceState,
 ) error {
-	igr.log.V(1).Info("Creating new resource", "resourceID", resourceID)
-
-	// Apply labels and create resource
-	igr.instanceSubResourcesLabeler.ApplyLabels(resource)
-	if _, err := rc.Create(ctx, resource, metav1.CreateOptions{}); err != nil {
+	igr.resourceLogger(resourceID).V(1).Info("Creating new resource")
+
+	// Apply labels and mutations, and create resource
+	igr.applyMetadata(resourceID, resource)
+	igr.applyMutations(resource, nil)
+	igr.recordManifest(resourceID, resource)
+	if ok, err := igr.checkPolicy(ctx, resourceID, resource, resourceState); !ok {
+		// Nothing was created, the dependent resources can't be resolved.
+		igr.runtime.IgnoreResource(resourceID)
+		return err
+	}
+	err := igr.retryTransient(ctx, func(ctx context.Context) error {
+		_, err := rc.Create(ctx, resource, metav1.CreateOptions{})
+		return err
+	})
+	igr.recordResourceEvent(resourceID, resource, resourceActionCreate, err)
+	if err != nil {
 		resourceState.State = ResourceStateError
 		resourceState.Err = fmt.Errorf("failed to create resource: %w", err)
 		return resourceState.Err
 	}
 
+	igr.observedResources.observe(igr.runtime.GetInstance(), resourceID, resource)
 	resourceState.State = ResourceStateCreated
 	return igr.delayedRequeue(fmt.Errorf("awaiting resource creation completion"))
 }
 
+// policyRejectedError is returned when resources were rejected by the
+// ReconcileConfig.PolicyCheck.
+type policyRejectedError struct {
+	resourceIDs []string
+	err         error
+}
+
+func (e *policyRejectedError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("resource %s rejected by policy: %v", e.resourceIDs[0], e.err)
+	}
+	return fmt.Sprintf("resources rejected by policy: %s", strings.Join(e.resourceIDs, ", "))
+}
+
+func (e *policyRejectedError) Unwrap() error {
+	return e.err
+}
+
+// checkPolicy runs the configured policy check against the resource about to
+// be applied. It returns false if the resource must not be applied, along with
+// an error if the reconciliation must stop.
+func (igr *instanceGraphReconciler) checkPolicy(
+	ctx context.Context,
+	resourceID string,
+	resource *unstructured.Unstructured,
+	resourceState *ResourceState,
+) (bool, error) {
+	if igr.reconcileConfig.PolicyCheck == nil {
+		return true, nil
+	}
+	err := igr.reconcileConfig.PolicyCheck(ctx, resource)
+	if err == nil {
+		return true, nil
+	}
+
+	resourceState.State = ResourceStateError
+	resourceState.Err = &policyRejectedError{resourceIDs: []string{resourceID}, err: err}
+	if igr.reconcileConfig.PolicyFailMode == PolicyFailModeAbort {
+		return false, resourceState.Err
+	}
+	igr.resourceLogger(resourceID).Info("Resource rejected by policy, not applying it", "error", err)
+	igr.policyRejected = append(igr.policyRejected, resourceID)
+	return false, nil
+}
+
 // updateResource handles updates to an existing resource, comparing the desired
 // and observed states and applying the necessary changes.
 func (igr *instanceGraphReconciler) updateResource(
@@ -269,7 +620,20 @@ func (igr *instanceGraphReconciler) updateResource(
 	resourceID string,
 	resourceState *ResourceState,
 ) error {
-	igr.log.V(1).Info("Processing resource update", "resourceID", resourceID)
+	log := igr.resourceLogger(resourceID)
+	log.V(1).Info("Processing resource update")
+
+	if err := igr.checkOwnership(resourceID, observed); err != nil {
+		resourceState.State = ResourceStateError
+		resourceState.Err = err
+		return err
+	}
+
+	// Apply labels, annotations and mutations before comparing, so that
+	// changes to the propagated instance metadata are picked up.
+	igr.applyMetadata(resourceID, desired)
+	igr.applyMutations(desired, observed)
+	igr.recordManifest(resourceID, desired)
 
 	// Compare desired and observed states
 	differences, err := delta.Compare(desired, observed)
@@ -282,7 +646,7 @@ func (igr *instanceGraphReconciler) updateResource(
 	// If no differences are found, the resource is in sync.
 	if len(differences) == 0 {
 		resourceState.State = ResourceStateSynced
-		igr.log.V(1).Info("No deltas found for resource", "resourceID", resourceID)
+		log.V(1).Info("No deltas found for resource")
 		return nil
 	}
 
@@ -290,17 +654,60 @@ func (igr *instanceGraphReconciler) updateResource(
 	// individually. We can apply all changes at once.
 	//
 	// NOTE(a-hilaly): are there any cases where we need to handle each difference individually?
-	igr.log.V(1).Info("Found deltas for resource",
-		"resourceID", resourceID,
-		"delta", differences,
-	)
-	igr.instanceSubResourcesLabeler.ApplyLabels(desired)
+	log.V(1).Info("Found deltas for resource", "delta", differences)
+
+	if ok, err := igr.checkPolicy(ctx, resourceID, desired, resourceState); !ok {
+		return err
+	}
 
 	// Apply changes to the resource
 	// TODO: Handle annotations
 	desired.SetResourceVersion(observed.GetResourceVersion())
 	desired.SetFinalizers(observed.GetFinalizers())
-	_, err = rc.Update(ctx, desired, metav1.UpdateOptions{})
+	update := func(ctx context.Context) error {
+		return igr.retryTransient(ctx, func(ctx context.Context) error {
+			_, err := rc.Update(ctx, desired, metav1.UpdateOptions{})
+			return err
+		})
+	}
+	// The resource modified since it was read goes through the same checks
+	// as the observed one: it may have been taken over by another instance,
+	// or already be in sync.
+	refresh := func(ctx context.Context) error {
+		log.V(1).Info("Resource modified since it was read, retrying the update")
+		getCtx, cancel := igr.resourceContext(ctx)
+		defer cancel()
+		current, err := rc.Get(getCtx, desired.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := igr.checkOwnership(resourceID, current); err != nil {
+			return err
+		}
+		desired.SetResourceVersion(current.GetResourceVersion())
+		desired.SetFinalizers(current.GetFinalizers())
+		differences, err := delta.Compare(desired, current)
+		if err != nil {
+			return fmt.Errorf("failed to compare desired and current states: %w", err)
+		}
+		if len(differences) == 0 {
+			return errResourceInSync
+		}
+		return nil
+	}
+	err = igr.retryConflict(ctx, update, refresh)
+	if errors.Is(err, errResourceInSync) {
+		resourceState.State = ResourceStateSynced
+		log.V(1).Info("Resource in sync after it was modified")
+		return nil
+	}
+	igr.recordResourceEvent(resourceID, desired, resourceActionUpdate, err)
+	var ownershipErr *ownershipConflictError
+	if errors.As(err, &ownershipErr) {
+		resourceState.State = ResourceStateError
+		resourceState.Err = err
+		return err
+	}
 	if err != nil {
 		resourceState.State = ResourceStateError
 		resourceState.Err = fmt.Errorf("failed to update resource: %w", err)
@@ -312,13 +719,44 @@ func (igr *instanceGraphReconciler) updateResource(
 	return igr.delayedRequeue(fmt.Errorf("resource update in progress"))
 }
 
+// errResourceInSync stops the retries of an update once the resource is found
+// in sync with its desired state.
+var errResourceInSync = errors.New("resource is in sync")
+
+// ownershipConflictError is returned when an existing resource is labeled as
+// managed by another instance.
+type ownershipConflictError struct {
+	resourceID string
+	owner      string
+	instance   string
+}
+
+func (e *ownershipConflictError) Error() string {
+	return fmt.Sprintf("resource %s is managed by instance %s, not by instance %s", e.resourceID, e.owner, e.instance)
+}
+
+// checkOwnership returns an ownershipConflictError if the observed resource is
+// labeled as managed by another instance, unless the resources can be adopted.
+// Resources without the label, e.g. created outside of kro, are adopted.
+func (igr *instanceGraphReconciler) checkOwnership(resourceID string, observed *unstructured.Unstructured) error {
+	if igr.reconcileConfig.AdoptResources {
+		return nil
+	}
+	owner, ok := observed.GetLabels()[metadata.InstanceIDLabel]
+	instance := string(igr.runtime.GetInstance().GetUID())
+	if !ok || owner == instance {
+		return nil
+	}
+	return &ownershipConflictError{resourceID: resourceID, owner: owner, instance: instance}
+}
+
 // handleInstanceDeletion manages the deletion of an instance and its resources
 // following the reverse topological order to respect dependencies.
 func (igr *instanceGraphReconciler) handleInstanceDeletion(ctx context.Context) error {
 	igr.log.V(1).Info("Beginning instance deletion process")
 
 	// Initialize deletion state for all resources
-	if err := igr.initializeDeletionState(); err != nil {
+	if err := igr.initializeDeletionState(ctx); err != nil {
 		return fmt.Errorf("failed to initialize deletion state: %w", err)
 	}
 
@@ -333,7 +771,7 @@ func (igr *instanceGraphReconciler) handleInstanceDeletion(ctx context.Context)
 
 // initializeDeletionState prepares resources for deletion by checking their
 // current state and marking them appropriately.
-func (igr *instanceGraphReconciler) initializeDeletionState() error {
+func (igr *instanceGraphReconciler) initializeDeletionState(ctx context.Context) error {
 	for _, resourceID := range igr.runtime.TopologicalOrder() {
 		if _, err := igr.runtime.Synchronize(); err != nil {
 			return fmt.Errorf("failed to synchronize during deletion state initialization: %w", err)
@@ -349,7 +787,9 @@ func (igr *instanceGraphReconciler) initializeDeletionState() error {
 
 		// Check if resource exists
 		rc := igr.getResourceClient(resourceID)
-		observed, err := rc.Get(context.TODO(), resource.GetName(), metav1.GetOptions{})
+		getCtx, cancel := igr.resourceContext(ctx)
+		observed, err := rc.Get(getCtx, resource.GetName(), metav1.GetOptions{})
+		cancel()
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				igr.state.ResourceStates[resourceID] = &ResourceState{
@@ -381,8 +821,27 @@ func (igr *instanceGraphReconciler) deleteResourcesInOrder(ctx context.Context)
 			continue
 		}
 
-		// Skip deletion for read-only resources
-		if igr.runtime.ResourceDescriptor(resourceID).IsExternalRef() {
+		// Skip deletion for read-only resources, unless they opted into being
+		// deleted with the instance
+		descriptor := igr.runtime.ResourceDescriptor(resourceID)
+		if descriptor.IsExternalRef() && !descriptor.DeleteWithInstance() {
+			igr.state.ResourceStates[resourceID].State = ResourceStateSkipped
+			continue
+		}
+
+		// Leave the resources protected by their live annotation in place
+		observed, _ := igr.runtime.GetResource(resourceID)
+		if metadata.IsPruneProtected(observed) {
+			igr.resourceLogger(resourceID).Info("Keeping resource protected from deletion",
+				"annotation", metadata.PruneProtectAnnotation)
+			igr.state.ResourceStates[resourceID].State = ResourceStateProtected
+			continue
+		}
+
+		// Never delete the resources managed by another instance, e.g. the
+		// ones this instance was refused the ownership of.
+		if err := igr.checkOwnership(resourceID, observed); err != nil {
+			igr.resourceLogger(resourceID).Info("Keeping resource managed by another instance", "reason", err.Error())
 			igr.state.ResourceStates[resourceID].State = ResourceStateSkipped
 			continue
 		}
@@ -396,33 +855,52 @@ func (igr *instanceGraphReconciler) deleteResourcesInOrder(ctx context.Context)
 
 // deleteResource handles the deletion of a single resource and updates its state.
 func (igr *instanceGraphReconciler) deleteResource(ctx context.Context, resourceID string) error {
-	igr.log.V(1).Info("Deleting resource", "resourceID", resourceID)
+	igr.resourceLogger(resourceID).V(1).Info("Deleting resource")
 
 	resource, _ := igr.runtime.GetResource(resourceID)
 	rc := igr.getResourceClient(resourceID)
 
 	// Attempt to delete the resource
-	err := rc.Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
+	ctx, cancel := igr.resourceContext(ctx)
+	defer cancel()
+	err := rc.Delete(ctx, resource.GetName(), igr.deleteOptions(resourceID))
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			igr.state.ResourceStates[resourceID].State = ResourceStateDeleted
 			return nil
 		}
+		igr.recordResourceEvent(resourceID, resource, resourceActionDelete, err)
 		igr.state.ResourceStates[resourceID].State = InstanceStateError
 		igr.state.ResourceStates[resourceID].Err = fmt.Errorf("failed to delete resource: %w", err)
 		return igr.state.ResourceStates[resourceID].Err
 	}
 
+	igr.recordResourceEvent(resourceID, resource, resourceActionDelete, nil)
 	igr.state.ResourceStates[resourceID].State = InstanceStateDeleting
 	return igr.delayedRequeue(fmt.Errorf("resource deletion in progress"))
 }
 
+// deleteOptions returns the options used to delete the resource, with the
+// propagation policy of the resource, or the configured one.
+func (igr *instanceGraphReconciler) deleteOptions(resourceID string) metav1.DeleteOptions {
+	policy := igr.runtime.ResourceDescriptor(resourceID).DeletionPropagation()
+	if policy == "" {
+		policy = igr.reconcileConfig.DeletionPropagation
+	}
+	if policy == "" {
+		return metav1.DeleteOptions{}
+	}
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
 // finalizeDeletion checks if all resources are deleted and removes the instance finalizer
 // if appropriate.
 func (igr *instanceGraphReconciler) finalizeDeletion(ctx context.Context) error {
 	// Check if all resources are deleted
 	for _, resourceState := range igr.state.ResourceStates {
-		if resourceState.State != ResourceStateDeleted && resourceState.State != ResourceStateSkipped {
+		switch resourceState.State {
+		case ResourceStateDeleted, ResourceStateSkipped, ResourceStateProtected:
+		default:
 			return igr.delayedRequeue(fmt.Errorf("waiting for resource deletion completion"))
 		}
 	}
@@ -434,20 +912,21 @@ func (igr *instanceGraphReconciler) finalizeDeletion(ctx context.Context) error
 		return fmt.Errorf("failed to remove instance finalizer: %w", err)
 	}
 
+	igr.observedResources.forget(instance)
 	igr.runtime.SetInstance(patched)
 	return nil
 }
 
 // setManaged ensures the instance has the necessary finalizer and labels.
 func (igr *instanceGraphReconciler) setManaged(ctx context.Context, obj *unstructured.Unstructured, _ types.UID) (*unstructured.Unstructured, error) {
-	if exist, _ := metadata.HasInstanceFinalizerUnstructured(obj); exist {
+	if exist, _ := metadata.HasInstanceFinalizerUnstructured(obj, igr.reconcileConfig.finalizer()); exist {
 		return obj, nil
 	}
 
 	igr.log.V(1).Info("Setting managed state", "name", obj.GetName(), "namespace", obj.GetNamespace())
 
 	copy := obj.DeepCopy()
-	if err := metadata.SetInstanceFinalizerUnstructured(copy); err != nil {
+	if err := metadata.SetInstanceFinalizerUnstructured(copy, igr.reconcileConfig.finalizer()); err != nil {
 		return nil, fmt.Errorf("failed to set finalizer: %w", err)
 	}
 
@@ -465,14 +944,14 @@ func (igr *instanceGraphReconciler) setManaged(ctx context.Context, obj *unstruc
 
 // setUnmanaged removes the finalizer from the instance.
 func (igr *instanceGraphReconciler) setUnmanaged(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	if exist, _ := metadata.HasInstanceFinalizerUnstructured(obj); !exist {
+	if exist, _ := metadata.HasInstanceFinalizerUnstructured(obj, igr.reconcileConfig.finalizer()); !exist {
 		return obj, nil
 	}
 
 	igr.log.V(1).Info("Removing managed state", "name", obj.GetName(), "namespace", obj.GetNamespace())
 
 	copy := obj.DeepCopy()
-	if err := metadata.RemoveInstanceFinalizerUnstructured(copy); err != nil {
+	if err := metadata.RemoveInstanceFinalizerUnstructured(copy, igr.reconcileConfig.finalizer()); err != nil {
 		return nil, fmt.Errorf("failed to remove finalizer: %w", err)
 	}
 
@@ -486,8 +965,106 @@ func (igr *instanceGraphReconciler) setUnmanaged(ctx context.Context, obj *unstr
 	return updated, nil
 }
 
+// applyMetadata applies the sub resources labels, the propagated instance
+// metadata and the id of the resource in the graph to the resource.
+func (igr *instanceGraphReconciler) applyMetadata(resourceID string, resource *unstructured.Unstructured) {
+	igr.subResourceMetadata.Apply(resourceID, resource)
+}
+
+// applyMutations applies the configured mutations to the resource, before
+// it is created or compared with its observed state. observed is nil when the
+// resource is created.
+func (igr *instanceGraphReconciler) applyMutations(resource, observed *unstructured.Unstructured) {
+	if igr.reconcileConfig.ImageRewrite != nil {
+		rewriteImages(resource, igr.reconcileConfig.ImageRewrite)
+	}
+	if igr.suspended {
+		suspendWorkload(resource, observed)
+	} else {
+		resumeWorkload(resource, observed)
+	}
+}
+
+// resourceContext returns the context to use for a single call against a
+// sub-resource, bounded by the configured ResourceTimeout if any.
+func (igr *instanceGraphReconciler) resourceContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if igr.reconcileConfig.ResourceTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, igr.reconcileConfig.ResourceTimeout)
+}
+
+// retryTransient calls fn, retrying it according to the TransientRetry
+// configuration as long as it fails with a transient error. Each attempt is
+// bounded by the ResourceTimeout.
+func (igr *instanceGraphReconciler) retryTransient(ctx context.Context, fn func(context.Context) error) error {
+	cfg := igr.reconcileConfig.TransientRetry
+	attempt := func() error {
+		ctx, cancel := igr.resourceContext(ctx)
+		defer cancel()
+		return fn(ctx)
+	}
+	if cfg.Attempts < 2 {
+		return attempt()
+	}
+
+	backoff := wait.Backoff{
+		Steps:    cfg.Attempts,
+		Duration: cfg.Backoff,
+		Factor:   2.0,
+		Jitter:   cfg.Jitter,
+	}
+	return retry.OnError(backoff, isTransientError, attempt)
+}
+
+// retryConflict calls fn, retrying it up to ConflictRetries times as long as
+// it fails with a conflict. refresh is called before each retry, to read the
+// current version of the resource. Once the retries are exhausted, the last
+// conflict is returned.
+func (igr *instanceGraphReconciler) retryConflict(
+	ctx context.Context,
+	fn func(context.Context) error,
+	refresh func(context.Context) error,
+) error {
+	cfg := igr.reconcileConfig.TransientRetry
+	if cfg.ConflictRetries < 1 {
+		return fn(ctx)
+	}
+
+	backoff := wait.Backoff{
+		Steps:    cfg.ConflictRetries + 1,
+		Duration: cfg.Backoff,
+		Factor:   2.0,
+		Jitter:   cfg.Jitter,
+	}
+	retrying := false
+	return retry.OnError(backoff, apierrors.IsConflict, func() error {
+		if retrying {
+			if err := refresh(ctx); err != nil {
+				return err
+			}
+		}
+		retrying = true
+		return fn(ctx)
+	})
+}
+
+// isTransientError returns true if the error is likely to go away by retrying
+// the same call.
+func isTransientError(err error) bool {
+	return apierrors.IsInternalError(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsUnexpectedServerError(err)
+}
+
 // delayedRequeue wraps an error with requeue information for the controller runtime.
 func (igr *instanceGraphReconciler) delayedRequeue(err error) error {
+	if igr.reconcileInterval > 0 {
+		return requeue.NeededAfter(err, igr.reconcileInterval)
+	}
 	return requeue.NeededAfter(err, igr.reconcileConfig.DefaultRequeueDuration)
 }
 
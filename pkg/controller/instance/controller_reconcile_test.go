@@ -0,0 +1,1383 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/requeue"
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// fakeRuntime only implements the parts of the runtime used by the tests
+// below, calling any other method panics.
+type fakeRuntime struct {
+	runtime.Interface
+	instance *unstructured.Unstructured
+}
+
+func (r fakeRuntime) GetInstance() *unstructured.Unstructured {
+	return r.instance
+}
+
+func (fakeRuntime) ResourceDescriptor(string) runtime.ResourceDescriptor {
+	return fakeDescriptor{}
+}
+
+func (fakeRuntime) IsInstanceReady() (bool, string, error) {
+	return true, "", nil
+}
+
+func (fakeRuntime) GetInstanceStatus() map[string]interface{} {
+	return nil
+}
+
+type fakeDescriptor struct {
+	runtime.ResourceDescriptor
+}
+
+func (fakeDescriptor) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+}
+
+// delayedResourceClient is a resource client whose Create calls take delay
+// to complete, unless the context is done first.
+type delayedResourceClient struct {
+	dynamic.ResourceInterface
+	delay time.Duration
+}
+
+func (c delayedResourceClient) Create(
+	ctx context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	select {
+	case <-time.After(c.delay):
+		return obj, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flakyResourceClient is a resource client whose first Create calls fail with
+// err.
+type flakyResourceClient struct {
+	dynamic.ResourceInterface
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyResourceClient) Create(
+	_ context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return obj, nil
+}
+
+func TestShouldApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		applied     []string
+		skipped     []string
+	}{
+		{
+			name:    "no apply-only annotation",
+			applied: []string{"deployment", "service", "configmap"},
+		},
+		{
+			name:        "apply-only subset",
+			annotations: map[string]string{metadata.ApplyOnlyAnnotation: "deployment,service"},
+			applied:     []string{"deployment", "service"},
+			skipped:     []string{"configmap"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				applyOnly: metadata.GetApplyOnlyResources(&metav1.ObjectMeta{Annotations: tt.annotations}),
+			}
+			for _, id := range tt.applied {
+				assert.True(t, igr.shouldApply(id), "expected %s to be applied", id)
+			}
+			for _, id := range tt.skipped {
+				assert.False(t, igr.shouldApply(id), "expected %s to be skipped", id)
+			}
+		})
+	}
+}
+
+func TestUnknownApplyOnlyResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name: "no apply-only annotation",
+		},
+		{
+			name:        "known resources",
+			annotations: map[string]string{metadata.ApplyOnlyAnnotation: "deployment,service"},
+		},
+		{
+			name:        "unknown resources",
+			annotations: map[string]string{metadata.ApplyOnlyAnnotation: "deployment,servcie,ingress"},
+			want:        []string{"ingress", "servcie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				runtime: graphRuntime{
+					configMapRuntime: configMapRuntime{fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}}},
+					resourceIDs:      []string{"deployment", "service", "configmap"},
+				},
+				applyOnly: metadata.GetApplyOnlyResources(&metav1.ObjectMeta{Annotations: tt.annotations}),
+				state:     newInstanceState(),
+			}
+			unknown := igr.unknownApplyOnlyResources()
+			assert.Equal(t, tt.want, unknown)
+
+			igr.state.UnknownApplyOnly = unknown
+			conditions := igr.prepareStatus()["conditions"].([]interface{})
+			if len(tt.want) == 0 {
+				require.Len(t, conditions, 1)
+				return
+			}
+			require.Len(t, conditions, 2)
+			condition := conditions[1].(map[string]interface{})
+			assert.Equal(t, ConditionApplyOnlyResourcesUnknown, condition["type"])
+			assert.Equal(t, ReasonNotInGraph, condition["reason"])
+			assert.Equal(t, "resources listed in the kro.run/apply-only annotation are not part of the graph: ingress, servcie",
+				condition["message"])
+		})
+	}
+}
+
+// applyOnlyRuntime is a config map runtime whose config map is always ready,
+// recording the resources ignored by the reconciler.
+type applyOnlyRuntime struct {
+	configMapRuntime
+	ignored map[string]bool
+}
+
+func (applyOnlyRuntime) SetResource(string, *unstructured.Unstructured) {}
+
+func (applyOnlyRuntime) IsResourceReady(string) (bool, string, error) {
+	return true, "", nil
+}
+
+func (r applyOnlyRuntime) IgnoreResource(id string) {
+	r.ignored[id] = true
+}
+
+func TestHandleResourceReconciliationApplyOnly(t *testing.T) {
+	newConfigMap := func(value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("default")
+		obj.SetName("config")
+		require.NoError(t, unstructured.SetNestedField(obj.Object, value, "data", "value"))
+		return obj
+	}
+	newReconciler := func(client dynamic.Interface, desired *unstructured.Unstructured) (*instanceGraphReconciler, applyOnlyRuntime) {
+		rt := applyOnlyRuntime{
+			configMapRuntime: configMapRuntime{
+				fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+				configMap:   desired,
+			},
+			ignored: map[string]bool{},
+		}
+		return &instanceGraphReconciler{
+			log:               logr.Discard(),
+			client:            client,
+			runtime:           rt,
+			applyOnly:         map[string]struct{}{"deployment": {}},
+			observedResources: newObservedResources(),
+			reconcileConfig:   ReconcileConfig{DefaultRequeueDuration: time.Second},
+			state:             newInstanceState(),
+		}, rt
+	}
+
+	t.Run("missing resource is not created", func(t *testing.T) {
+		desired := newConfigMap("desired")
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+		igr, rt := newReconciler(client, desired)
+		resourceState := &ResourceState{State: ResourceStateInProgress}
+
+		require.NoError(t, igr.handleResourceReconciliation(context.Background(), "configmap", desired, resourceState))
+		assert.Equal(t, ResourceStateSkipped, resourceState.State)
+		assert.True(t, rt.ignored["configmap"], "dependents of a skipped resource must not wait on it")
+		for _, action := range client.Actions() {
+			assert.NotEqual(t, "create", action.GetVerb())
+		}
+	})
+
+	t.Run("existing resource is not updated", func(t *testing.T) {
+		desired := newConfigMap("desired")
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), newConfigMap("live"))
+		igr, _ := newReconciler(client, desired)
+		resourceState := &ResourceState{State: ResourceStateInProgress}
+
+		require.NoError(t, igr.handleResourceReconciliation(context.Background(), "configmap", desired, resourceState))
+		assert.Equal(t, ResourceStateSynced, resourceState.State)
+		for _, action := range client.Actions() {
+			assert.NotEqual(t, "update", action.GetVerb())
+		}
+	})
+}
+
+func TestGatesReadiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		readyGate []string
+		gated     []string
+		ungated   []string
+	}{
+		{
+			name:  "no ready gate",
+			gated: []string{"deployment", "service", "configmap"},
+		},
+		{
+			name:      "ready gate subset",
+			readyGate: []string{"deployment"},
+			gated:     []string{"deployment"},
+			ungated:   []string{"service", "configmap"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				readyGate: newResourceSet(tt.readyGate),
+			}
+			for _, id := range tt.gated {
+				assert.True(t, igr.gatesReadiness(id), "expected %s to gate readiness", id)
+			}
+			for _, id := range tt.ungated {
+				assert.False(t, igr.gatesReadiness(id), "expected %s not to gate readiness", id)
+			}
+		})
+	}
+}
+
+func TestLoggerCorrelationValues(t *testing.T) {
+	var lines []string
+	log := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	instance := &metav1.ObjectMeta{Name: "my-app", Namespace: "team-a", UID: "1234"}
+	log = withInstanceValues(log.WithValues("namespace", instance.Namespace, "name", instance.Name), instance)
+	log = withResourceValues(log, "deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	log.Info("Creating new resource")
+
+	assert.Len(t, lines, 1)
+	for _, kv := range []string{
+		`"namespace"="team-a"`,
+		`"name"="my-app"`,
+		`"uid"="1234"`,
+		`"resourceID"="deployment"`,
+		`"gvr"="apps/v1, Resource=deployments"`,
+	} {
+		assert.Contains(t, lines[0], kv)
+	}
+}
+
+func TestHandleResourceCreationTimeout(t *testing.T) {
+	newReconciler := func(timeout time.Duration) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:     logr.Discard(),
+			runtime: fakeRuntime{},
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				ResourceTimeout:        timeout,
+			},
+		}
+	}
+	newConfigMap := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("test")
+		return obj
+	}
+
+	t.Run("call slower than the timeout", func(t *testing.T) {
+		state := &ResourceState{}
+		err := newReconciler(50*time.Millisecond).handleResourceCreation(
+			context.Background(), delayedResourceClient{delay: time.Minute}, newConfigMap(), "configmap", state,
+		)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, ResourceStateError, state.State)
+	})
+
+	t.Run("call faster than the timeout", func(t *testing.T) {
+		state := &ResourceState{}
+		err := newReconciler(time.Minute).handleResourceCreation(
+			context.Background(), delayedResourceClient{delay: time.Millisecond}, newConfigMap(), "configmap", state,
+		)
+		// a successful creation requeues to wait for the resource
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, ResourceStateCreated, state.State)
+	})
+}
+
+// hangingClient is a dynamic client whose Get calls only return once the
+// context is done.
+type hangingClient struct {
+	dynamic.NamespaceableResourceInterface
+}
+
+func (c hangingClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c
+}
+
+func (c hangingClient) Namespace(string) dynamic.ResourceInterface {
+	return c
+}
+
+func (hangingClient) Get(
+	ctx context.Context, _ string, _ metav1.GetOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// synchronizedConfigMapRuntime is an orderedConfigMapRuntime that can be
+// synchronized, as done when deleting an instance.
+type synchronizedConfigMapRuntime struct {
+	orderedConfigMapRuntime
+}
+
+func (synchronizedConfigMapRuntime) Synchronize() (bool, error) {
+	return false, nil
+}
+
+func TestResourceReadTimeout(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace("default")
+	configMap.SetName("config")
+
+	newReconciler := func() *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:    logr.Discard(),
+			client: hangingClient{},
+			runtime: synchronizedConfigMapRuntime{orderedConfigMapRuntime{configMapRuntime{
+				fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+				configMap:   configMap,
+			}}},
+			observedResources: newObservedResources(),
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				ResourceTimeout:        50 * time.Millisecond,
+			},
+			state: newInstanceState(),
+		}
+	}
+
+	t.Run("reconciliation", func(t *testing.T) {
+		resourceState := &ResourceState{State: ResourceStateInProgress}
+		err := newReconciler().handleResourceReconciliation(
+			context.Background(), "configmap", configMap.DeepCopy(), resourceState,
+		)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("deletion", func(t *testing.T) {
+		err := newReconciler().initializeDeletionState(context.Background())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestHandleResourceCreationTransientRetry(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+	newReconciler := func(attempts int) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:     logr.Discard(),
+			runtime: fakeRuntime{},
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				TransientRetry: TransientRetryConfig{
+					Attempts: attempts,
+					Backoff:  time.Millisecond,
+					Jitter:   0.5,
+				},
+			},
+		}
+	}
+	newConfigMap := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("test")
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		attempts  int
+		err       error
+		wantCalls int
+		wantState string
+	}{
+		{
+			name:      "transient errors are retried",
+			attempts:  3,
+			err:       apierrors.NewInternalError(errors.New("etcd leader changed")),
+			wantCalls: 3,
+			wantState: ResourceStateCreated,
+		},
+		{
+			name:      "server timeouts are retried",
+			attempts:  3,
+			err:       apierrors.NewServerTimeout(gr, "create", 1),
+			wantCalls: 3,
+			wantState: ResourceStateCreated,
+		},
+		{
+			name:      "attempts are bounded",
+			attempts:  2,
+			err:       apierrors.NewServiceUnavailable("unavailable"),
+			wantCalls: 2,
+			wantState: ResourceStateError,
+		},
+		{
+			name:      "retries are disabled by default",
+			attempts:  0,
+			err:       apierrors.NewInternalError(errors.New("etcd leader changed")),
+			wantCalls: 1,
+			wantState: ResourceStateError,
+		},
+		{
+			name:      "other errors are not retried",
+			attempts:  3,
+			err:       apierrors.NewBadRequest("invalid"),
+			wantCalls: 1,
+			wantState: ResourceStateError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &flakyResourceClient{failures: 2, err: tt.err}
+			state := &ResourceState{}
+			err := newReconciler(tt.attempts).handleResourceCreation(
+				context.Background(), client, newConfigMap(), "configmap", state,
+			)
+			// a successful creation requeues to wait for the resource
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCalls, client.calls)
+			assert.Equal(t, tt.wantState, state.State)
+		})
+	}
+}
+
+func TestApplyMetadataPrecedence(t *testing.T) {
+	newResource := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetLabels(map[string]string{"team": "template", metadata.InstanceIDLabel: "template"})
+		obj.SetAnnotations(map[string]string{"owner": "template"})
+		return obj
+	}
+	newReconciler := func(templatePrecedence bool) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			subResourceMetadata: metadata.SubResourceMetadata{
+				Labeler:               metadata.GenericLabeler{metadata.InstanceIDLabel: "uid"},
+				PropagatedLabels:      map[string]string{"team": "instance", "environment": "prod"},
+				PropagatedAnnotations: map[string]string{"owner": "instance"},
+				TemplatePrecedence:    templatePrecedence,
+			},
+		}
+	}
+
+	t.Run("instance precedence", func(t *testing.T) {
+		resource := newResource()
+		newReconciler(false).applyMetadata("configmap", resource)
+		assert.Equal(t, map[string]string{
+			"team":                   "instance",
+			"environment":            "prod",
+			metadata.InstanceIDLabel: "uid",
+		}, resource.GetLabels())
+		assert.Equal(t, map[string]string{
+			"owner":                       "instance",
+			metadata.ResourceIDAnnotation: "configmap",
+		}, resource.GetAnnotations())
+	})
+
+	t.Run("template precedence", func(t *testing.T) {
+		resource := newResource()
+		newReconciler(true).applyMetadata("configmap", resource)
+		assert.Equal(t, map[string]string{
+			"team":                   "template",
+			"environment":            "prod",
+			metadata.InstanceIDLabel: "uid",
+		}, resource.GetLabels())
+		assert.Equal(t, map[string]string{
+			"owner":                       "template",
+			metadata.ResourceIDAnnotation: "configmap",
+		}, resource.GetAnnotations())
+	})
+}
+
+// orderedConfigMapRuntime is a config map runtime with a topological order.
+type orderedConfigMapRuntime struct {
+	configMapRuntime
+}
+
+func (orderedConfigMapRuntime) TopologicalOrder() []string {
+	return []string{"configmap"}
+}
+
+func TestDelayedRequeueReconcileInterval(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+	}{
+		{"default", nil, 10 * time.Second},
+		{"configured interval", map[string]string{metadata.ReconcileIntervalAnnotation: "2m"}, 2 * time.Minute},
+		{"invalid interval", map[string]string{metadata.ReconcileIntervalAnnotation: "soon"}, 10 * time.Second},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &unstructured.Unstructured{}
+			instance.SetAnnotations(tt.annotations)
+			igr := &instanceGraphReconciler{
+				log:             logr.Discard(),
+				runtime:         fakeRuntime{instance: instance},
+				reconcileConfig: ReconcileConfig{DefaultRequeueDuration: 10 * time.Second},
+			}
+			igr.reconcileInterval = igr.instanceReconcileInterval(instance)
+
+			err := igr.delayedRequeue(errors.New("resource not ready"))
+			var requeueErr *requeue.RequeueNeededAfter
+			require.ErrorAs(t, err, &requeueErr)
+			assert.Equal(t, tt.want, requeueErr.Duration())
+		})
+	}
+}
+
+func TestDeleteResourcesInOrderPruneProtect(t *testing.T) {
+	for _, protected := range []bool{false, true} {
+		configMap := &unstructured.Unstructured{}
+		configMap.SetAPIVersion("v1")
+		configMap.SetKind("ConfigMap")
+		configMap.SetNamespace("default")
+		configMap.SetName("config")
+		if protected {
+			configMap.SetAnnotations(map[string]string{metadata.PruneProtectAnnotation: "true"})
+		}
+
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), configMap.DeepCopy())
+		igr := &instanceGraphReconciler{
+			log:    logr.Discard(),
+			client: client,
+			runtime: orderedConfigMapRuntime{configMapRuntime{
+				fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+				configMap:   configMap,
+			}},
+			reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+			state:           newInstanceState(),
+		}
+		igr.state.ResourceStates["configmap"] = &ResourceState{State: ResourceStatePendingDeletion}
+
+		err := igr.deleteResourcesInOrder(context.Background())
+		_, getErr := client.Resource(fakeDescriptor{}.GetGroupVersionResource()).Namespace("default").Get(
+			context.Background(), "config", metav1.GetOptions{},
+		)
+		if protected {
+			require.NoError(t, err)
+			require.NoError(t, getErr, "a protected resource must not be deleted")
+			assert.Equal(t, ResourceStateProtected, igr.state.ResourceStates["configmap"].State)
+		} else {
+			require.Error(t, err)
+			assert.True(t, apierrors.IsNotFound(getErr))
+			assert.Equal(t, InstanceStateDeleting, igr.state.ResourceStates["configmap"].State)
+		}
+	}
+}
+
+func TestDeleteResourcesInOrderOwnershipConflict(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetUID("instance-uid")
+
+	for _, tt := range []struct {
+		name        string
+		owner       string
+		adopt       bool
+		wantDeleted bool
+	}{
+		{name: "resource managed by another instance is kept", owner: "other-uid"},
+		{name: "resource managed by another instance is deleted once adopted", owner: "other-uid", adopt: true, wantDeleted: true},
+		{name: "resource managed by the instance is deleted", owner: "instance-uid", wantDeleted: true},
+		{name: "resource without owner is deleted", wantDeleted: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &unstructured.Unstructured{}
+			configMap.SetAPIVersion("v1")
+			configMap.SetKind("ConfigMap")
+			configMap.SetNamespace("default")
+			configMap.SetName("config")
+			if tt.owner != "" {
+				configMap.SetLabels(map[string]string{metadata.InstanceIDLabel: tt.owner})
+			}
+
+			client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), configMap.DeepCopy())
+			igr := &instanceGraphReconciler{
+				log:    logr.Discard(),
+				client: client,
+				runtime: orderedConfigMapRuntime{configMapRuntime{
+					fakeRuntime: fakeRuntime{instance: instance},
+					configMap:   configMap,
+				}},
+				reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second, AdoptResources: tt.adopt},
+				state:           newInstanceState(),
+			}
+			igr.state.ResourceStates["configmap"] = &ResourceState{State: ResourceStatePendingDeletion}
+
+			err := igr.deleteResourcesInOrder(context.Background())
+			_, getErr := client.Resource(fakeDescriptor{}.GetGroupVersionResource()).Namespace("default").Get(
+				context.Background(), "config", metav1.GetOptions{},
+			)
+			if tt.wantDeleted {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsNotFound(getErr))
+				assert.Equal(t, InstanceStateDeleting, igr.state.ResourceStates["configmap"].State)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, getErr, "a resource managed by another instance must not be deleted")
+				assert.Equal(t, ResourceStateSkipped, igr.state.ResourceStates["configmap"].State)
+			}
+		})
+	}
+}
+
+// externalRefRuntime is a config map runtime whose config map is an external
+// reference.
+type externalRefRuntime struct {
+	orderedConfigMapRuntime
+	deleteWithInstance bool
+}
+
+func (r externalRefRuntime) ResourceDescriptor(string) runtime.ResourceDescriptor {
+	return externalRefDescriptor{deleteWithInstance: r.deleteWithInstance}
+}
+
+type externalRefDescriptor struct {
+	configMapDescriptor
+	deleteWithInstance bool
+}
+
+func (externalRefDescriptor) IsExternalRef() bool {
+	return true
+}
+
+func (d externalRefDescriptor) DeleteWithInstance() bool {
+	return d.deleteWithInstance
+}
+
+func TestDeleteResourcesInOrderExternalRef(t *testing.T) {
+	for _, deleteWithInstance := range []bool{false, true} {
+		configMap := &unstructured.Unstructured{}
+		configMap.SetAPIVersion("v1")
+		configMap.SetKind("ConfigMap")
+		configMap.SetNamespace("default")
+		configMap.SetName("config")
+
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), configMap.DeepCopy())
+		igr := &instanceGraphReconciler{
+			log:    logr.Discard(),
+			client: client,
+			runtime: externalRefRuntime{
+				orderedConfigMapRuntime: orderedConfigMapRuntime{configMapRuntime{
+					fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+					configMap:   configMap,
+				}},
+				deleteWithInstance: deleteWithInstance,
+			},
+			reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+			state:           newInstanceState(),
+		}
+		igr.state.ResourceStates["configmap"] = &ResourceState{State: ResourceStatePendingDeletion}
+
+		err := igr.deleteResourcesInOrder(context.Background())
+		_, getErr := client.Resource(fakeDescriptor{}.GetGroupVersionResource()).Namespace("default").Get(
+			context.Background(), "config", metav1.GetOptions{},
+		)
+		if deleteWithInstance {
+			require.Error(t, err)
+			assert.True(t, apierrors.IsNotFound(getErr))
+			assert.Equal(t, InstanceStateDeleting, igr.state.ResourceStates["configmap"].State)
+		} else {
+			require.NoError(t, err)
+			require.NoError(t, getErr, "an external reference must not be deleted")
+			assert.Equal(t, ResourceStateSkipped, igr.state.ResourceStates["configmap"].State)
+		}
+	}
+}
+
+// propagationRuntime is a config map runtime whose config map sets its own
+// deletion propagation policy.
+type propagationRuntime struct {
+	configMapRuntime
+	policy metav1.DeletionPropagation
+}
+
+func (r propagationRuntime) ResourceDescriptor(string) runtime.ResourceDescriptor {
+	return propagationDescriptor{policy: r.policy}
+}
+
+type propagationDescriptor struct {
+	configMapDescriptor
+	policy metav1.DeletionPropagation
+}
+
+func (d propagationDescriptor) DeletionPropagation() metav1.DeletionPropagation {
+	return d.policy
+}
+
+func TestDeleteOptionsPropagation(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource metav1.DeletionPropagation
+		config   metav1.DeletionPropagation
+		want     *metav1.DeletionPropagation
+	}{
+		{name: "default policy of the kind"},
+		{name: "configured policy", config: metav1.DeletePropagationForeground, want: ptr.To(metav1.DeletePropagationForeground)},
+		{name: "resource policy", resource: metav1.DeletePropagationOrphan, want: ptr.To(metav1.DeletePropagationOrphan)},
+		{
+			name:     "resource policy overrides the configured one",
+			resource: metav1.DeletePropagationOrphan,
+			config:   metav1.DeletePropagationForeground,
+			want:     ptr.To(metav1.DeletePropagationOrphan),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				runtime:         propagationRuntime{policy: tt.resource},
+				reconcileConfig: ReconcileConfig{DeletionPropagation: tt.config},
+			}
+			assert.Equal(t, tt.want, igr.deleteOptions("configmap").PropagationPolicy)
+		})
+	}
+}
+
+func TestHandleResourceReconciliationExternalRefNotFound(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace("default")
+	configMap.SetName("config")
+
+	igr := &instanceGraphReconciler{
+		log:    logr.Discard(),
+		client: dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme()),
+		runtime: externalRefRuntime{
+			orderedConfigMapRuntime: orderedConfigMapRuntime{configMapRuntime{
+				fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+				configMap:   configMap,
+			}},
+		},
+		reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+		state:           newInstanceState(),
+	}
+	resourceState := &ResourceState{State: ResourceStateInProgress}
+
+	err := igr.handleResourceReconciliation(context.Background(), "configmap", configMap, resourceState)
+	require.Error(t, err)
+
+	var requeueErr *requeue.RequeueNeededAfter
+	require.ErrorAs(t, err, &requeueErr, "a missing external reference must be requeued")
+	var notFound *externalRefNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, "external reference configmap not found: v1 ConfigMap default/config", notFound.Error())
+	assert.Equal(t, ReasonExternalRefNotFound, reconcileFailureReason(err))
+	assert.Equal(t, "WAITING_FOR_EXTERNAL_RESOURCE", resourceState.State)
+}
+
+// onceRuntime is a config map runtime whose config map is only reconciled
+// once, and always ready.
+type onceRuntime struct {
+	configMapRuntime
+}
+
+func (onceRuntime) ResourceDescriptor(string) runtime.ResourceDescriptor {
+	return onceDescriptor{}
+}
+
+func (onceRuntime) SetResource(string, *unstructured.Unstructured) {}
+
+func (onceRuntime) IsResourceReady(string) (bool, string, error) {
+	return true, "", nil
+}
+
+type onceDescriptor struct {
+	configMapDescriptor
+}
+
+func (onceDescriptor) ReconcileOnce() bool {
+	return true
+}
+
+func TestHandleResourceReconciliationReconcileOnce(t *testing.T) {
+	live := &unstructured.Unstructured{}
+	live.SetAPIVersion("v1")
+	live.SetKind("ConfigMap")
+	live.SetNamespace("default")
+	live.SetName("config")
+	require.NoError(t, unstructured.SetNestedField(live.Object, "seed", "data", "value"))
+
+	desired := live.DeepCopy()
+	require.NoError(t, unstructured.SetNestedField(desired.Object, "changed", "data", "value"))
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), live)
+	igr := &instanceGraphReconciler{
+		log:    logr.Discard(),
+		client: client,
+		runtime: onceRuntime{configMapRuntime{
+			fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}},
+			configMap:   desired,
+		}},
+		observedResources: newObservedResources(),
+		reconcileConfig:   ReconcileConfig{DefaultRequeueDuration: time.Second},
+		state:             newInstanceState(),
+	}
+	resourceState := &ResourceState{State: ResourceStateInProgress}
+
+	err := igr.handleResourceReconciliation(context.Background(), "configmap", desired, resourceState)
+	require.NoError(t, err)
+	assert.Equal(t, ResourceStateSynced, resourceState.State)
+
+	observed, err := client.Resource(fakeDescriptor{}.GetGroupVersionResource()).Namespace("default").Get(
+		context.Background(), "config", metav1.GetOptions{},
+	)
+	require.NoError(t, err)
+	value, _, _ := unstructured.NestedString(observed.Object, "data", "value")
+	assert.Equal(t, "seed", value, "a resource reconciled once must not be updated")
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "update", action.GetVerb())
+	}
+}
+
+// versionedResourceClient is a resource client rejecting the updates whose
+// resourceVersion differs from the one of the live object, as the API server
+// does.
+type versionedResourceClient struct {
+	dynamic.ResourceInterface
+	resourceVersion string
+	updates         int
+	gets            int
+	// racing simulates another writer, updating the object each time it is
+	// read.
+	racing bool
+	// live, if set, is the content of the live object returned by Get.
+	live *unstructured.Unstructured
+	// getDeadlines records whether each Get was bounded by a deadline.
+	getDeadlines []bool
+}
+
+func (c *versionedResourceClient) Get(
+	ctx context.Context, name string, _ metav1.GetOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	c.gets++
+	_, hasDeadline := ctx.Deadline()
+	c.getDeadlines = append(c.getDeadlines, hasDeadline)
+	obj := &unstructured.Unstructured{}
+	if c.live != nil {
+		obj = c.live.DeepCopy()
+	}
+	obj.SetName(name)
+	obj.SetResourceVersion(c.resourceVersion)
+	if c.racing {
+		c.resourceVersion += "+"
+	}
+	return obj, nil
+}
+
+func (c *versionedResourceClient) Update(
+	_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	if obj.GetResourceVersion() != c.resourceVersion {
+		return nil, apierrors.NewConflict(
+			schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+			errors.New("the object has been modified"),
+		)
+	}
+	c.updates++
+	return obj, nil
+}
+
+func TestUpdateResourceVersionPrecondition(t *testing.T) {
+	newConfigMap := func(value, resourceVersion string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("config")
+		obj.SetResourceVersion(resourceVersion)
+		_ = unstructured.SetNestedField(obj.Object, value, "data", "key")
+		return obj
+	}
+	igr := &instanceGraphReconciler{
+		log:             logr.Discard(),
+		runtime:         fakeRuntime{instance: &unstructured.Unstructured{}},
+		reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+	}
+
+	t.Run("object unchanged since read", func(t *testing.T) {
+		client := &versionedResourceClient{resourceVersion: "1"}
+		state := &ResourceState{}
+		err := igr.updateResource(context.Background(), client,
+			newConfigMap("desired", ""), newConfigMap("observed", "1"), "configmap", state)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State)
+		assert.Equal(t, 1, client.updates)
+	})
+
+	t.Run("object changed since read", func(t *testing.T) {
+		// The object was modified externally after it was read.
+		client := &versionedResourceClient{resourceVersion: "2"}
+		state := &ResourceState{}
+		err := igr.updateResource(context.Background(), client,
+			newConfigMap("desired", ""), newConfigMap("observed", "1"), "configmap", state)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, ResourceStateError, state.State)
+		assert.Equal(t, 0, client.updates)
+	})
+}
+
+func TestUpdateResourceConflictRetries(t *testing.T) {
+	newConfigMap := func(value, resourceVersion string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("config")
+		obj.SetResourceVersion(resourceVersion)
+		_ = unstructured.SetNestedField(obj.Object, value, "data", "key")
+		return obj
+	}
+	newReconciler := func(retries int) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:     logr.Discard(),
+			runtime: fakeRuntime{instance: &unstructured.Unstructured{}},
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				TransientRetry: TransientRetryConfig{
+					Backoff:         time.Millisecond,
+					ConflictRetries: retries,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		retries   int
+		racing    bool
+		wantGets  int
+		wantState string
+	}{
+		{
+			name:      "conflicts are retried with the current version",
+			retries:   3,
+			wantGets:  1,
+			wantState: ResourceStateUpdating,
+		},
+		{
+			name:      "retries are disabled by default",
+			retries:   0,
+			wantGets:  0,
+			wantState: ResourceStateError,
+		},
+		{
+			name:      "retries are bounded",
+			retries:   2,
+			racing:    true,
+			wantGets:  2,
+			wantState: ResourceStateError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The object was modified externally after it was read.
+			client := &versionedResourceClient{resourceVersion: "2", racing: tt.racing}
+			state := &ResourceState{}
+			err := newReconciler(tt.retries).updateResource(context.Background(), client,
+				newConfigMap("desired", ""), newConfigMap("observed", "1"), "configmap", state)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantState, state.State)
+			assert.Equal(t, tt.wantGets, client.gets)
+			if tt.wantState == ResourceStateError {
+				assert.True(t, apierrors.IsConflict(err), "the last conflict must be returned, got %v", err)
+				assert.Equal(t, 0, client.updates)
+			} else {
+				assert.Equal(t, 1, client.updates)
+			}
+		})
+	}
+}
+
+func TestUpdateResourceConflictRefresh(t *testing.T) {
+	newConfigMap := func(value, resourceVersion, owner string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("config")
+		obj.SetResourceVersion(resourceVersion)
+		if owner != "" {
+			obj.SetLabels(map[string]string{metadata.InstanceIDLabel: owner})
+		}
+		_ = unstructured.SetNestedField(obj.Object, value, "data", "key")
+		return obj
+	}
+	instance := &unstructured.Unstructured{}
+	instance.SetUID("instance-uid")
+	igr := &instanceGraphReconciler{
+		log:     logr.Discard(),
+		runtime: fakeRuntime{instance: instance},
+		reconcileConfig: ReconcileConfig{
+			DefaultRequeueDuration: time.Second,
+			ResourceTimeout:        time.Minute,
+			TransientRetry: TransientRetryConfig{
+				Backoff:         time.Millisecond,
+				ConflictRetries: 3,
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		live        *unstructured.Unstructured
+		wantState   string
+		wantUpdates int
+		wantErr     bool
+	}{
+		{
+			name:        "resource still out of sync",
+			live:        newConfigMap("external", "", "instance-uid"),
+			wantState:   ResourceStateUpdating,
+			wantUpdates: 1,
+			wantErr:     true,
+		},
+		{
+			name: "resource already in sync",
+			live: func() *unstructured.Unstructured {
+				live := newConfigMap("desired", "", "instance-uid")
+				live.SetAnnotations(map[string]string{metadata.ResourceIDAnnotation: "configmap"})
+				return live
+			}(),
+			wantState: ResourceStateSynced,
+		},
+		{
+			name:      "resource taken over by another instance",
+			live:      newConfigMap("external", "", "other-uid"),
+			wantState: ResourceStateError,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The object was modified externally after it was read.
+			client := &versionedResourceClient{resourceVersion: "2", live: tt.live}
+			state := &ResourceState{}
+			err := igr.updateResource(context.Background(), client,
+				newConfigMap("desired", "", ""), newConfigMap("observed", "1", "instance-uid"), "configmap", state)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantState, state.State)
+			assert.Equal(t, tt.wantUpdates, client.updates)
+			assert.Equal(t, []bool{true}, client.getDeadlines, "the refresh must be bounded by the resource timeout")
+			if tt.wantState == ResourceStateError {
+				var ownershipErr *ownershipConflictError
+				assert.ErrorAs(t, err, &ownershipErr)
+			}
+		})
+	}
+}
+
+func TestUpdateResourceOwnershipConflict(t *testing.T) {
+	newConfigMap := func(value, owner string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("config")
+		obj.SetResourceVersion("1")
+		if owner != "" {
+			obj.SetLabels(map[string]string{metadata.InstanceIDLabel: owner})
+		}
+		_ = unstructured.SetNestedField(obj.Object, value, "data", "key")
+		return obj
+	}
+	instance := &unstructured.Unstructured{}
+	instance.SetUID("instance-uid")
+	newReconciler := func(adopt bool) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:     logr.Discard(),
+			runtime: fakeRuntime{instance: instance},
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				AdoptResources:         adopt,
+			},
+		}
+	}
+
+	t.Run("resource managed by another instance is refused", func(t *testing.T) {
+		client := &versionedResourceClient{resourceVersion: "1"}
+		state := &ResourceState{}
+		err := newReconciler(false).updateResource(context.Background(), client,
+			newConfigMap("desired", ""), newConfigMap("observed", "other-uid"), "configmap", state)
+		require.Error(t, err)
+		assert.Equal(t, "resource configmap is managed by instance other-uid, not by instance instance-uid", err.Error())
+		assert.Equal(t, ReasonOwnershipConflict, reconcileFailureReason(err))
+		assert.Equal(t, ResourceStateError, state.State)
+		assert.Equal(t, 0, client.updates)
+	})
+
+	t.Run("resource managed by another instance is adopted", func(t *testing.T) {
+		client := &versionedResourceClient{resourceVersion: "1"}
+		state := &ResourceState{}
+		err := newReconciler(true).updateResource(context.Background(), client,
+			newConfigMap("desired", ""), newConfigMap("observed", "other-uid"), "configmap", state)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State)
+		assert.Equal(t, 1, client.updates)
+	})
+
+	for _, owner := range []string{"", "instance-uid"} {
+		client := &versionedResourceClient{resourceVersion: "1"}
+		state := &ResourceState{}
+		err := newReconciler(false).updateResource(context.Background(), client,
+			newConfigMap("desired", ""), newConfigMap("observed", owner), "configmap", state)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State, "owner %q", owner)
+		assert.Equal(t, 1, client.updates, "owner %q", owner)
+	}
+}
+
+// graphRuntime is a runtime with config maps at each of its resource ids.
+type graphRuntime struct {
+	configMapRuntime
+	resourceIDs []string
+}
+
+func (r graphRuntime) TopologicalOrder() []string {
+	return r.resourceIDs
+}
+
+func TestReconcileInstanceMaxObjects(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("config")
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	igr := &instanceGraphReconciler{
+		log:    logr.Discard(),
+		client: client,
+		runtime: graphRuntime{
+			configMapRuntime: configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}, configMap: configMap},
+			resourceIDs:      []string{"config1", "config2", "config3"},
+		},
+		reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second, MaxObjectsPerInstance: 3},
+		state:           newInstanceState(),
+	}
+	require.NoError(t, igr.checkObjectCount())
+
+	// The graph expands to more objects than allowed.
+	igr.reconcileConfig.MaxObjectsPerInstance = 2
+	err := igr.reconcileInstance(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "instance manages 3 objects, more than the limit of 2: nothing was applied", err.Error())
+	assert.Equal(t, ReasonTooManyObjects, reconcileFailureReason(err))
+	assert.Empty(t, client.Actions(), "nothing should be applied")
+}
+
+func TestConfiguredFinalizer(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"}
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "WebApp",
+		"metadata": map[string]interface{}{
+			"name":      "my-app",
+			"namespace": "default",
+			// Set by another kro deployment.
+			"finalizers": []interface{}{metadata.DefaultFinalizer},
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), instance.DeepCopy())
+	igr := &instanceGraphReconciler{
+		log:             logr.Discard(),
+		gvr:             gvr,
+		client:          client,
+		instanceLabeler: metadata.GenericLabeler{},
+		reconcileConfig: ReconcileConfig{Finalizer: "team-a.kro.run/finalizer"},
+	}
+
+	managed, err := igr.setManaged(context.Background(), instance, instance.GetUID())
+	require.NoError(t, err)
+	assert.Equal(t, []string{metadata.DefaultFinalizer, "team-a.kro.run/finalizer"}, managed.GetFinalizers())
+
+	unmanaged, err := igr.setUnmanaged(context.Background(), managed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{metadata.DefaultFinalizer}, unmanaged.GetFinalizers())
+
+	// An instance without the configured finalizer isn't this deployment's
+	// to release.
+	unchanged, err := igr.setUnmanaged(context.Background(), unmanaged)
+	require.NoError(t, err)
+	assert.Equal(t, []string{metadata.DefaultFinalizer}, unchanged.GetFinalizers())
+}
+
+func TestHandleResourceCreationResourceIDAnnotation(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("config")
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	gvr := fakeDescriptor{}.GetGroupVersionResource()
+	igr := &instanceGraphReconciler{
+		log:             logr.Discard(),
+		runtime:         fakeRuntime{instance: &unstructured.Unstructured{}},
+		reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+	}
+	err := igr.handleResourceCreation(context.Background(), client.Resource(gvr).Namespace("default"),
+		configMap, "configmap", &ResourceState{})
+	require.Error(t, err)
+
+	created, err := client.Resource(gvr).Namespace("default").Get(context.Background(), "config", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "configmap", created.GetAnnotations()[metadata.ResourceIDAnnotation])
+}
+
+// ignoringRuntime records the resources ignored by the reconciler.
+type ignoringRuntime struct {
+	fakeRuntime
+	ignored map[string]bool
+}
+
+func (r ignoringRuntime) IgnoreResource(id string) {
+	r.ignored[id] = true
+}
+
+func TestHandleResourceCreationPolicyCheck(t *testing.T) {
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		return obj
+	}
+	policyCheck := func(_ context.Context, obj *unstructured.Unstructured) error {
+		// The policy sees the object as it would be applied.
+		if obj.GetAnnotations()[metadata.ResourceIDAnnotation] == "" {
+			return errors.New("missing resource id annotation")
+		}
+		if obj.GetName() == "forbidden" {
+			return errors.New("name is not allowed")
+		}
+		return nil
+	}
+	gvr := fakeDescriptor{}.GetGroupVersionResource()
+	newReconciler := func(mode PolicyFailMode) (*instanceGraphReconciler, ignoringRuntime) {
+		rt := ignoringRuntime{fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}}, ignored: map[string]bool{}}
+		return &instanceGraphReconciler{
+			log:     logr.Discard(),
+			runtime: rt,
+			reconcileConfig: ReconcileConfig{
+				DefaultRequeueDuration: time.Second,
+				PolicyCheck:            policyCheck,
+				PolicyFailMode:         mode,
+			},
+		}, rt
+	}
+
+	t.Run("skip the rejected resource", func(t *testing.T) {
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+		rc := client.Resource(gvr).Namespace("default")
+		igr, rt := newReconciler(PolicyFailModeSkip)
+
+		allowedState := &ResourceState{}
+		err := igr.handleResourceCreation(context.Background(), rc, newConfigMap("allowed"), "allowed", allowedState)
+		require.Error(t, err, "a successful creation requeues")
+		assert.Equal(t, ResourceStateCreated, allowedState.State)
+
+		rejectedState := &ResourceState{}
+		err = igr.handleResourceCreation(context.Background(), rc, newConfigMap("forbidden"), "forbidden", rejectedState)
+		require.NoError(t, err, "the other resources are still reconciled")
+		assert.Equal(t, ResourceStateError, rejectedState.State)
+		assert.EqualError(t, rejectedState.Err, "resource forbidden rejected by policy: name is not allowed")
+		assert.Equal(t, []string{"forbidden"}, igr.policyRejected)
+		assert.True(t, rt.ignored["forbidden"], "the dependent resources are skipped")
+
+		_, err = rc.Get(context.Background(), "forbidden", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "the rejected resource isn't created")
+		assert.Equal(t, ReasonPolicyRejected, reconcileFailureReason(&policyRejectedError{resourceIDs: igr.policyRejected}))
+	})
+
+	t.Run("abort on the rejected resource", func(t *testing.T) {
+		client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+		rc := client.Resource(gvr).Namespace("default")
+		igr, _ := newReconciler(PolicyFailModeAbort)
+
+		state := &ResourceState{}
+		err := igr.handleResourceCreation(context.Background(), rc, newConfigMap("forbidden"), "forbidden", state)
+		require.Error(t, err)
+		assert.Equal(t, ReasonPolicyRejected, reconcileFailureReason(err))
+		assert.Equal(t, ResourceStateError, state.State)
+		assert.Empty(t, client.Actions(), "nothing should be applied")
+	})
+}
@@ -16,16 +16,80 @@ package instance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/metadata"
 	"github.com/kro-run/kro/pkg/requeue"
 )
 
+const (
+	// FieldManager is the field manager used to apply the status of the
+	// instances.
+	FieldManager = "kro"
+
+	// ReasonReconciliationFailed is the InstanceSynced reason for generic
+	// reconciliation failures.
+	ReasonReconciliationFailed = "ReconciliationFailed"
+	// ReasonForbidden is the InstanceSynced reason used when kro lacks the
+	// permissions to manage a resource.
+	ReasonForbidden = "Forbidden"
+	// ReasonQuotaExceeded is the InstanceSynced reason used when a resource
+	// can't be created because of a namespace ResourceQuota.
+	ReasonQuotaExceeded = "QuotaExceeded"
+	// ReasonTooManyObjects is the InstanceSynced reason used when the instance
+	// manages more objects than the controller allows.
+	ReasonTooManyObjects = "TooManyObjects"
+	// ReasonPolicyRejected is the InstanceSynced reason used when resources
+	// were rejected by the configured policy check.
+	ReasonPolicyRejected = "PolicyRejected"
+	// ReasonExternalRefNotFound is the InstanceSynced reason used when an
+	// object referenced by an external reference doesn't exist.
+	ReasonExternalRefNotFound = "ExternalRefNotFound"
+	// ReasonOwnershipConflict is the InstanceSynced reason used when a
+	// resource is managed by another instance.
+	ReasonOwnershipConflict = "OwnershipConflict"
+
+	// ConditionResourcesRecreated is set when resources deleted outside of
+	// kro were recreated during the reconciliation.
+	ConditionResourcesRecreated = "ResourcesRecreated"
+	// ReasonDeletedExternally is the ResourcesRecreated reason.
+	ReasonDeletedExternally = "DeletedExternally"
+
+	// ConditionApplyOnlyResourcesUnknown is set when the apply-only annotation
+	// of the instance lists resources that are not part of the graph.
+	ConditionApplyOnlyResourcesUnknown = "ApplyOnlyResourcesUnknown"
+	// ReasonNotInGraph is the ApplyOnlyResourcesUnknown reason.
+	ReasonNotInGraph = "NotInGraph"
+
+	// EventReasonReady is the reason of the event recorded when an instance
+	// becomes ready for its generation.
+	EventReasonReady = "Ready"
+	// EventReasonNotReady is the reason of the event recorded when a ready
+	// instance stops being ready.
+	EventReasonNotReady = "NotReady"
+	// EventReasonResourceApplied is the reason of the event recorded when a
+	// resource of the instance is created or updated.
+	EventReasonResourceApplied = "ResourceApplied"
+	// EventReasonResourceApplyFailed is the reason of the event recorded when
+	// a resource of the instance fails to be created or updated.
+	EventReasonResourceApplyFailed = "ResourceApplyFailed"
+	// EventReasonResourceDeleted is the reason of the event recorded when a
+	// resource is deleted along with the instance.
+	EventReasonResourceDeleted = "ResourceDeleted"
+	// EventReasonResourceDeleteFailed is the reason of the event recorded when
+	// a resource fails to be deleted along with the instance.
+	EventReasonResourceDeleteFailed = "ResourceDeleteFailed"
+)
+
 func createCondition(conditionType v1alpha1.ConditionType, status corev1.ConditionStatus, reason, message string, generation int64) map[string]interface{} {
 	return map[string]interface{}{
 		"type":               string(conditionType),
@@ -43,26 +107,77 @@ func (igr *instanceGraphReconciler) prepareStatus() map[string]interface{} {
 	generation := igr.runtime.GetInstance().GetGeneration()
 
 	status["state"] = igr.state.State
-	status["conditions"] = igr.prepareConditions(igr.state.ReconcileErr, generation)
+	conditions := igr.prepareConditions(igr.state.ReconcileErr, generation)
+	if len(igr.state.Recreated) > 0 {
+		conditions = append(conditions, createCondition(
+			ConditionResourcesRecreated,
+			corev1.ConditionTrue,
+			ReasonDeletedExternally,
+			fmt.Sprintf("resources deleted outside of kro were recreated: %s", strings.Join(igr.state.Recreated, ", ")),
+			generation,
+		))
+	}
+	if len(igr.state.UnknownApplyOnly) > 0 {
+		conditions = append(conditions, createCondition(
+			ConditionApplyOnlyResourcesUnknown,
+			corev1.ConditionTrue,
+			ReasonNotInGraph,
+			fmt.Sprintf("resources listed in the %s annotation are not part of the graph: %s",
+				metadata.ApplyOnlyAnnotation, strings.Join(igr.state.UnknownApplyOnly, ", ")),
+			generation,
+		))
+	}
+	status["conditions"] = conditions
+	status["observedRGDGeneration"] = igr.rgdGeneration
+
+	// The resource states only reflect the applied resources outside of the
+	// deletion, the last summary is kept while the instance is deleted.
+	instance := igr.runtime.GetInstance()
+	if instance.GetDeletionTimestamp().IsZero() {
+		status["resourceSummary"] = igr.resourceSummary()
+	} else if summary, ok, _ := unstructured.NestedMap(instance.Object, "status", "resourceSummary"); ok {
+		status["resourceSummary"] = summary
+	}
 
 	return status
 }
 
-// getResolvedStatus retrieves the current status while preserving non-condition fields.
-func (igr *instanceGraphReconciler) getResolvedStatus() map[string]interface{} {
-	status := map[string]interface{}{
-		"conditions": []interface{}{},
-	}
-
-	if existingStatus, ok := igr.runtime.GetInstance().Object["status"].(map[string]interface{}); ok {
-		// Copy existing status but reset conditions
-		for k, v := range existingStatus {
-			if k != "conditions" {
-				status[k] = v
-			}
+// resourceSummary counts the resources managed by the instance, by outcome of
+// the reconciliation: the desired resources are the ones included in the
+// graph, the applied ones exist in the cluster, and the errored ones failed to
+// be reconciled. External references are not managed, they are not counted.
+func (igr *instanceGraphReconciler) resourceSummary() map[string]interface{} {
+	var desired, applied, errored int64
+	for resourceID, resourceState := range igr.state.ResourceStates {
+		if resourceState.State == ResourceStateSkipped ||
+			igr.runtime.ResourceDescriptor(resourceID).IsExternalRef() {
+			continue
+		}
+		desired++
+		switch resourceState.State {
+		case ResourceStateCreated, ResourceStateUpdating, ResourceStateSynced, ResourceStateWaitingForReadiness:
+			applied++
+		case ResourceStateError:
+			errored++
 		}
 	}
+	return map[string]interface{}{
+		"desired": desired,
+		"applied": applied,
+		"errored": errored,
+	}
+}
 
+// getResolvedStatus returns the status fields computed by kro from the status
+// expressions of the resource graph definition. The other status fields are
+// left out: they belong to other field managers, and the fields kro no longer
+// computes are dropped by the server-side apply.
+func (igr *instanceGraphReconciler) getResolvedStatus() map[string]interface{} {
+	status := map[string]interface{}{}
+	for k, v := range igr.runtime.GetInstanceStatus() {
+		status[k] = v
+	}
+	status["conditions"] = []interface{}{}
 	return status
 }
 
@@ -78,7 +193,7 @@ func (igr *instanceGraphReconciler) prepareConditions(
 		conditions = append(conditions, createCondition(
 			"InstanceSynced",
 			corev1.ConditionFalse,
-			"ReconciliationFailed",
+			reconcileFailureReason(reconcileErr),
 			reconcileErr.Error(),
 			generation,
 		))
@@ -95,14 +210,60 @@ func (igr *instanceGraphReconciler) prepareConditions(
 	return conditions
 }
 
-// patchInstanceStatus updates the status subresource of the instance.
+// reconcileFailureReason classifies the reconciliation error, so that RBAC and
+// quota errors, which are fixed by the cluster operators, can be told apart
+// from the other failures.
+func reconcileFailureReason(err error) string {
+	var tooManyObjects *tooManyObjectsError
+	var policyRejected *policyRejectedError
+	var externalRefNotFound *externalRefNotFoundError
+	var ownershipConflict *ownershipConflictError
+	switch {
+	case errors.As(err, &tooManyObjects):
+		return ReasonTooManyObjects
+	case errors.As(err, &policyRejected):
+		return ReasonPolicyRejected
+	case errors.As(err, &externalRefNotFound):
+		return ReasonExternalRefNotFound
+	case errors.As(err, &ownershipConflict):
+		return ReasonOwnershipConflict
+	case isQuotaExceeded(err):
+		return ReasonQuotaExceeded
+	case apierrors.IsForbidden(err):
+		return ReasonForbidden
+	default:
+		return ReasonReconciliationFailed
+	}
+}
+
+// isQuotaExceeded returns true if the error was returned by the ResourceQuota
+// admission plugin. Quota errors are Forbidden errors, only their message tells
+// them apart.
+func isQuotaExceeded(err error) bool {
+	return apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
+}
+
+// patchInstanceStatus applies the status subresource of the instance. The
+// status is server-side applied with the kro field manager: conditions are
+// merged by type, so the conditions set by other controllers are preserved.
 func (igr *instanceGraphReconciler) patchInstanceStatus(ctx context.Context, status map[string]interface{}) error {
-	instance := igr.runtime.GetInstance().DeepCopy()
-	instance.Object["status"] = status
+	instance := igr.runtime.GetInstance()
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": instance.GetAPIVersion(),
+		"kind":       instance.GetKind(),
+		"metadata": map[string]interface{}{
+			"name":      instance.GetName(),
+			"namespace": instance.GetNamespace(),
+		},
+		"status": status,
+	}}
 
 	_, err := igr.client.Resource(igr.gvr).
 		Namespace(instance.GetNamespace()).
-		UpdateStatus(ctx, instance, metav1.UpdateOptions{})
+		ApplyStatus(ctx, instance.GetName(), patch, metav1.ApplyOptions{
+			FieldManager: FieldManager,
+			Force:        true,
+		})
 
 	if err != nil {
 		return fmt.Errorf("failed to update instance status: %w", err)
@@ -124,3 +285,90 @@ func (igr *instanceGraphReconciler) updateInstanceState() {
 		}
 	}
 }
+
+// recordReadinessTransition records an event when the instance becomes ready
+// for its generation, and a warning when a ready instance stops being ready.
+// The previous readiness is read from the status written by the previous
+// reconciliation.
+func (igr *instanceGraphReconciler) recordReadinessTransition() {
+	recorder := igr.reconcileConfig.EventRecorder
+	if recorder == nil {
+		return
+	}
+
+	instance := igr.runtime.GetInstance()
+	wasReady, readyGeneration := previousReadiness(instance)
+	ready := igr.state.State == InstanceStateActive
+	switch {
+	case ready && (!wasReady || readyGeneration != instance.GetGeneration()):
+		recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonReady,
+			"Instance %s is ready", instance.GetName())
+	case !ready && wasReady && igr.state.State != InstanceStateDeleting:
+		recorder.Eventf(instance, corev1.EventTypeWarning, EventReasonNotReady,
+			"Instance %s is no longer ready, state is %s", instance.GetName(), igr.state.State)
+	}
+}
+
+// Actions taken on the resources of an instance, recorded as events.
+const (
+	resourceActionCreate = "create"
+	resourceActionUpdate = "update"
+	resourceActionDelete = "delete"
+)
+
+// recordResourceEvent records an event on the instance for an action taken on
+// one of its resources, naming the resource kind and name. The event is a
+// warning if the action failed.
+func (igr *instanceGraphReconciler) recordResourceEvent(
+	resourceID string,
+	resource *unstructured.Unstructured,
+	action string,
+	err error,
+) {
+	recorder := igr.reconcileConfig.EventRecorder
+	if recorder == nil {
+		return
+	}
+
+	name := resource.GetName()
+	if igr.runtime.ResourceDescriptor(resourceID).IsNamespaced() {
+		name = igr.getResourceNamespace(resourceID) + "/" + name
+	}
+	gvk := resource.GroupVersionKind()
+	object := fmt.Sprintf("%s (%s %s %s)", resourceID, gvk.GroupVersion().String(), gvk.Kind, name)
+
+	instance := igr.runtime.GetInstance()
+	reason, failedReason := EventReasonResourceApplied, EventReasonResourceApplyFailed
+	if action == resourceActionDelete {
+		reason, failedReason = EventReasonResourceDeleted, EventReasonResourceDeleteFailed
+	}
+	if err != nil {
+		recorder.Eventf(instance, corev1.EventTypeWarning, failedReason,
+			"Failed to %s resource %s: %v", action, object, err)
+		return
+	}
+	recorder.Eventf(instance, corev1.EventTypeNormal, reason, "Resource %s %sd", object, action)
+}
+
+// previousReadiness returns whether the status of the instance reports it
+// ready, along with the generation the status was computed for.
+func previousReadiness(instance *unstructured.Unstructured) (bool, int64) {
+	state, _, _ := unstructured.NestedString(instance.Object, "status", "state")
+	if state != InstanceStateActive {
+		return false, 0
+	}
+	conditions, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "InstanceSynced" {
+			continue
+		}
+		switch generation := condition["observedGeneration"].(type) {
+		case int64:
+			return true, generation
+		case float64:
+			return true, int64(generation)
+		}
+	}
+	return true, 0
+}
@@ -0,0 +1,420 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/requeue"
+)
+
+func TestReconcileFailureReason(t *testing.T) {
+	podsResource := schema.GroupResource{Resource: "pods"}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "generic error",
+			err:  errors.New("something went wrong"),
+			want: ReasonReconciliationFailed,
+		},
+		{
+			name: "forbidden error",
+			err: fmt.Errorf("failed to create resource: %w", apierrors.NewForbidden(
+				podsResource, "my-pod", errors.New(`User "system:serviceaccount:kro:kro" cannot create resource "pods"`),
+			)),
+			want: ReasonForbidden,
+		},
+		{
+			name: "quota error",
+			err: fmt.Errorf("failed to create resource: %w", apierrors.NewForbidden(
+				podsResource, "my-pod", errors.New("exceeded quota: compute-resources, requested: pods=1, used: pods=10, limited: pods=10"),
+			)),
+			want: ReasonQuotaExceeded,
+		},
+		{
+			name: "requeued forbidden error",
+			err: requeue.NeededAfter(fmt.Errorf("failed to update resource: %w", apierrors.NewForbidden(
+				podsResource, "my-pod", errors.New("denied"),
+			)), time.Second),
+			want: ReasonForbidden,
+		},
+		{
+			name: "requeued external reference not found",
+			err: requeue.NeededAfter(&externalRefNotFoundError{
+				resourceID: "database",
+				gvk:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				namespace:  "default",
+				name:       "db",
+				err:        apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "db"),
+			}, time.Second),
+			want: ReasonExternalRefNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, reconcileFailureReason(tt.err))
+
+			igr := &instanceGraphReconciler{}
+			conditions := igr.prepareConditions(tt.err, 1)
+			require.Len(t, conditions, 1)
+			assert.Equal(t, tt.want, conditions[0].(map[string]interface{})["reason"])
+		})
+	}
+}
+
+func TestPatchInstanceStatus(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"}
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "WebApp",
+		"metadata": map[string]interface{}{
+			"name":      "my-app",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"image": "nginx",
+		},
+	}}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "WebAppList"},
+	)
+	var patch k8stesting.PatchActionImpl
+	client.PrependReactor("patch", "webapps", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		patch = action.(k8stesting.PatchActionImpl)
+		return true, instance, nil
+	})
+
+	igr := &instanceGraphReconciler{
+		gvr:     gvr,
+		client:  client,
+		runtime: fakeRuntime{instance: instance},
+	}
+	status := map[string]interface{}{
+		"state": InstanceStateActive,
+		"conditions": []interface{}{
+			createCondition("InstanceSynced", "True", "ReconciliationSucceeded", "", 1),
+		},
+	}
+	require.NoError(t, igr.patchInstanceStatus(context.Background(), status))
+
+	// The status is server-side applied by kro, so the conditions owned by
+	// other field managers are left untouched.
+	assert.Equal(t, "status", patch.GetSubresource())
+	assert.Equal(t, types.ApplyPatchType, patch.PatchType)
+
+	applied := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(patch.Patch, &applied))
+	assert.NotContains(t, applied, "spec")
+	assert.Equal(t, map[string]interface{}{"name": "my-app", "namespace": "default"}, applied["metadata"])
+	conditions := applied["status"].(map[string]interface{})["conditions"].([]interface{})
+	require.Len(t, conditions, 1)
+	assert.Equal(t, "InstanceSynced", conditions[0].(map[string]interface{})["type"])
+}
+
+// skippingRuntime is a runtime whose resources are all skipped.
+type skippingRuntime struct {
+	fakeRuntime
+	resources []string
+}
+
+func (r skippingRuntime) TopologicalOrder() []string {
+	return r.resources
+}
+
+func (skippingRuntime) ReadyToProcessResource(string) (bool, error) {
+	return false, nil
+}
+
+func (skippingRuntime) IgnoreResource(string) {}
+
+func (skippingRuntime) Synchronize() (bool, error) {
+	return false, nil
+}
+
+func TestReconcileWritesStatusOnce(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"}
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "WebApp",
+		"metadata": map[string]interface{}{
+			"name":      "my-app",
+			"namespace": "default",
+		},
+	}}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "WebAppList"},
+		instance.DeepCopy(),
+	)
+	var statusWrites int
+	client.PrependReactor("*", "webapps", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		if action.GetSubresource() == "status" {
+			statusWrites++
+			return true, instance, nil
+		}
+		return false, nil, nil
+	})
+
+	igr := &instanceGraphReconciler{
+		log:             logr.Discard(),
+		gvr:             gvr,
+		client:          client,
+		runtime:         skippingRuntime{fakeRuntime: fakeRuntime{instance: instance}, resources: []string{"a", "b", "c"}},
+		instanceLabeler: metadata.GenericLabeler{},
+	}
+	require.NoError(t, igr.reconcile(context.Background()))
+
+	// The state, the conditions and the state of every resource change during
+	// the reconciliation, but the status is only written once, at the end.
+	assert.Equal(t, 1, statusWrites)
+	assert.Equal(t, InstanceStateActive, igr.state.State)
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, ResourceStateSkipped, igr.state.ResourceStates[id].State)
+	}
+}
+
+func TestRecordReadinessTransition(t *testing.T) {
+	newInstance := func(generation int64, state string, observedGeneration int64) *unstructured.Unstructured {
+		instance := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "WebApp",
+			"metadata": map[string]interface{}{
+				"name":       "my-app",
+				"namespace":  "default",
+				"generation": generation,
+			},
+		}}
+		if state != "" {
+			instance.Object["status"] = map[string]interface{}{
+				"state": state,
+				"conditions": []interface{}{
+					createCondition("InstanceSynced", "True", "ReconciliationSucceeded", "", observedGeneration),
+				},
+			}
+		}
+		return instance
+	}
+
+	tests := []struct {
+		name     string
+		instance *unstructured.Unstructured
+		state    string
+		want     []string
+	}{
+		{
+			name:     "first time ready",
+			instance: newInstance(1, "", 0),
+			state:    InstanceStateActive,
+			want:     []string{"Normal Ready Instance my-app is ready"},
+		},
+		{
+			name:     "still ready",
+			instance: newInstance(1, InstanceStateActive, 1),
+			state:    InstanceStateActive,
+		},
+		{
+			name:     "ready for a new generation",
+			instance: newInstance(2, InstanceStateActive, 1),
+			state:    InstanceStateActive,
+			want:     []string{"Normal Ready Instance my-app is ready"},
+		},
+		{
+			name:     "regression",
+			instance: newInstance(1, InstanceStateActive, 1),
+			state:    InstanceStateError,
+			want:     []string{"Warning NotReady Instance my-app is no longer ready, state is ERROR"},
+		},
+		{
+			name:     "not ready yet",
+			instance: newInstance(1, InstanceStateInProgress, 1),
+			state:    InstanceStateInProgress,
+		},
+		{
+			name:     "deleting",
+			instance: newInstance(1, InstanceStateActive, 1),
+			state:    InstanceStateDeleting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			igr := &instanceGraphReconciler{
+				runtime:         fakeRuntime{instance: tt.instance},
+				reconcileConfig: ReconcileConfig{EventRecorder: recorder},
+				state:           &InstanceState{State: tt.state},
+			}
+			igr.recordReadinessTransition()
+			close(recorder.Events)
+
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecordResourceEvents(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("config")
+
+	recorder := record.NewFakeRecorder(10)
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	igr := &instanceGraphReconciler{
+		log:             logr.Discard(),
+		client:          client,
+		runtime:         configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}, configMap: configMap},
+		reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second, EventRecorder: recorder},
+		state:           newInstanceState(),
+	}
+	rc := client.Resource(fakeDescriptor{}.GetGroupVersionResource()).Namespace("default")
+
+	require.Error(t, igr.handleResourceCreation(context.Background(), rc, configMap.DeepCopy(), "configmap", &ResourceState{}))
+	failing := &flakyResourceClient{failures: 1, err: apierrors.NewBadRequest("invalid")}
+	require.Error(t, igr.handleResourceCreation(context.Background(), failing, configMap.DeepCopy(), "configmap", &ResourceState{}))
+	igr.state.ResourceStates["configmap"] = &ResourceState{State: ResourceStatePendingDeletion}
+	require.Error(t, igr.deleteResource(context.Background(), "configmap"))
+	close(recorder.Events)
+
+	var got []string
+	for event := range recorder.Events {
+		got = append(got, event)
+	}
+	assert.Equal(t, []string{
+		"Normal ResourceApplied Resource configmap (v1 ConfigMap default/config) created",
+		"Warning ResourceApplyFailed Failed to create resource configmap (v1 ConfigMap default/config): invalid",
+		"Normal ResourceDeleted Resource configmap (v1 ConfigMap default/config) deleted",
+	}, got)
+}
+
+func TestPrepareStatusResourceSummary(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	igr := &instanceGraphReconciler{
+		log:     logr.Discard(),
+		runtime: configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}},
+		state:   newInstanceState(),
+	}
+	igr.state.State = InstanceStateInProgress
+	igr.state.ResourceStates = map[string]*ResourceState{
+		"synced":   {State: ResourceStateSynced},
+		"created":  {State: ResourceStateCreated},
+		"waiting":  {State: ResourceStateWaitingForReadiness},
+		"errored":  {State: ResourceStateError, Err: errors.New("failed to update resource")},
+		"pending":  {State: ResourceStatePending},
+		"excluded": {State: ResourceStateSkipped},
+	}
+
+	status := igr.prepareStatus()
+	assert.Equal(t, map[string]interface{}{
+		"desired": int64(5),
+		"applied": int64(3),
+		"errored": int64(1),
+	}, status["resourceSummary"])
+
+	// The summary of the applied resources is left as is during the deletion
+	now := metav1.Now()
+	instance.SetDeletionTimestamp(&now)
+	instance.Object["status"] = map[string]interface{}{
+		"resourceSummary": map[string]interface{}{"desired": int64(5)},
+	}
+	status = igr.prepareStatus()
+	assert.Equal(t, map[string]interface{}{"desired": int64(5)}, status["resourceSummary"])
+}
+
+func TestPrepareStatusObservedRGDGeneration(t *testing.T) {
+	igr := &instanceGraphReconciler{
+		log:           logr.Discard(),
+		runtime:       configMapRuntime{fakeRuntime: fakeRuntime{instance: &unstructured.Unstructured{}}},
+		state:         newInstanceState(),
+		rgdGeneration: 3,
+	}
+	status := igr.prepareStatus()
+	assert.Equal(t, int64(3), status["observedRGDGeneration"])
+}
+
+// statusRuntime is a config map runtime whose instance has computed status
+// fields.
+type statusRuntime struct {
+	configMapRuntime
+	status map[string]interface{}
+}
+
+func (r statusRuntime) GetInstanceStatus() map[string]interface{} {
+	return r.status
+}
+
+func TestPrepareStatusOnlyComputedFields(t *testing.T) {
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"endpoint": "stale",
+			// Set by another controller
+			"external": "value",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "External", "status": "True"},
+			},
+		},
+	}}
+	igr := &instanceGraphReconciler{
+		log: logr.Discard(),
+		runtime: statusRuntime{
+			configMapRuntime: configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}},
+			status:           map[string]interface{}{"endpoint": "my-app.default.svc"},
+		},
+		state: newInstanceState(),
+	}
+	igr.state.State = InstanceStateActive
+
+	// The fields of other field managers are not applied by kro, so it doesn't
+	// take their ownership.
+	status := igr.prepareStatus()
+	assert.Equal(t, "my-app.default.svc", status["endpoint"])
+	assert.Equal(t, InstanceStateActive, status["state"])
+	assert.NotContains(t, status, "external")
+	conditions := status["conditions"].([]interface{})
+	require.Len(t, conditions, 1)
+	assert.Equal(t, "InstanceSynced", conditions[0].(map[string]interface{})["type"])
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kroclient "github.com/kro-run/kro/pkg/client"
+)
+
+func TestGetInstanceExecutionClient(t *testing.T) {
+	var impersonated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		impersonated = append(impersonated, r.Header.Get("Impersonate-User"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"team-a"}}`))
+	}))
+	defer server.Close()
+
+	clientSet, err := kroclient.NewSet(kroclient.Config{RestConfig: &rest.Config{Host: server.URL}})
+	require.NoError(t, err)
+
+	c := &Controller{
+		log:       logr.Discard(),
+		clientSet: clientSet,
+		reconcileConfig: ReconcileConfig{
+			AllowedServiceAccounts: []string{"team-a/deployer", "*/kro-apply"},
+		},
+	}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	t.Run("allowed service account", func(t *testing.T) {
+		impersonated = nil
+		client, err := c.getInstanceExecutionClient("team-a", "deployer")
+		require.NoError(t, err)
+
+		_, err = client.Resource(configMaps).Namespace("team-a").Get(context.Background(), "config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system:serviceaccount:team-a:deployer"}, impersonated)
+	})
+
+	t.Run("service account allowed in any namespace", func(t *testing.T) {
+		impersonated = nil
+		client, err := c.getInstanceExecutionClient("team-b", "kro-apply")
+		require.NoError(t, err)
+
+		_, err = client.Resource(configMaps).Namespace("team-b").Get(context.Background(), "config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system:serviceaccount:team-b:kro-apply"}, impersonated)
+	})
+
+	t.Run("service account not allowed", func(t *testing.T) {
+		_, err := c.getInstanceExecutionClient("team-b", "deployer")
+		assert.ErrorContains(t, err, "service account team-b/deployer is not allowed")
+	})
+
+	t.Run("no allowed service accounts", func(t *testing.T) {
+		c := &Controller{log: logr.Discard(), clientSet: clientSet}
+		_, err := c.getInstanceExecutionClient("team-a", "deployer")
+		assert.Error(t, err)
+	})
+
+	t.Run("service account of another namespace", func(t *testing.T) {
+		_, err := c.getInstanceExecutionClient("team-b", "team-a/deployer")
+		assert.ErrorContains(t, err, "must live in the namespace team-b of the instance")
+	})
+
+	t.Run("cluster scoped instance", func(t *testing.T) {
+		impersonated = nil
+		client, err := c.getInstanceExecutionClient("", "team-a/deployer")
+		require.NoError(t, err)
+
+		_, err = client.Resource(configMaps).Namespace("team-a").Get(context.Background(), "config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system:serviceaccount:team-a:deployer"}, impersonated)
+	})
+
+	t.Run("cluster scoped instance without service account namespace", func(t *testing.T) {
+		_, err := c.getInstanceExecutionClient("", "deployer")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetExecutionClient(t *testing.T) {
+	var impersonated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		impersonated = append(impersonated, r.Header.Get("Impersonate-User"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"team-a"}}`))
+	}))
+	defer server.Close()
+
+	clientSet, err := kroclient.NewSet(kroclient.Config{RestConfig: &rest.Config{Host: server.URL}})
+	require.NoError(t, err)
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	tests := []struct {
+		name                   string
+		defaultServiceAccounts map[string]string
+		namespace              string
+		wantImpersonated       string
+		wantErr                string
+	}{
+		{
+			name:                   "namespace service account",
+			defaultServiceAccounts: map[string]string{"team-a": "deployer", "*": "kro"},
+			namespace:              "team-a",
+			wantImpersonated:       "system:serviceaccount:team-a:deployer",
+		},
+		{
+			name:                   "default service account",
+			defaultServiceAccounts: map[string]string{"team-a": "deployer", "*": "kro"},
+			namespace:              "team-b",
+			wantImpersonated:       "system:serviceaccount:team-b:kro",
+		},
+		{
+			name:                   "cluster scoped instance",
+			defaultServiceAccounts: map[string]string{"team-a": "deployer", "*": "kro-system/kro"},
+			namespace:              "",
+			wantImpersonated:       "system:serviceaccount:kro-system:kro",
+		},
+		{
+			name:                   "cluster scoped instance without service account namespace",
+			defaultServiceAccounts: map[string]string{"*": "kro"},
+			namespace:              "",
+			wantErr:                "cluster scoped instances must reference it as <namespace>/<name>",
+		},
+		{
+			name:                   "cluster scoped instance without default service account",
+			defaultServiceAccounts: map[string]string{"team-a": "deployer"},
+			namespace:              "",
+			wantImpersonated:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impersonated = nil
+			c := &Controller{
+				log:                    logr.Discard(),
+				clientSet:              clientSet,
+				defaultServiceAccounts: tt.defaultServiceAccounts,
+			}
+			client, err := c.getExecutionClient(tt.namespace)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = client.Resource(configMaps).Namespace("team-a").Get(context.Background(), "config", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.wantImpersonated}, impersonated)
+		})
+	}
+}
+
+func TestGetNamespaceName(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		wantNamespace string
+		wantName      string
+	}{
+		{name: "namespaced instance", key: "team-a/my-app", wantNamespace: "team-a", wantName: "my-app"},
+		{name: "empty namespace", key: "/my-app", wantNamespace: metav1.NamespaceDefault, wantName: "my-app"},
+		{name: "cluster scoped instance", key: "my-app", wantNamespace: "", wantName: "my-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, name := getNamespaceName(ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.key}})
+			assert.Equal(t, tt.wantNamespace, namespace)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podSpecPaths maps the workload kinds to the path of their pod spec.
+var podSpecPaths = map[schema.GroupKind][]string{
+	{Group: "", Kind: "Pod"}:                   {"spec"},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template", "spec"},
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template", "spec"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template", "spec"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template", "spec"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template", "spec"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template", "spec"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// containerFields are the pod spec fields holding containers.
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// rewriteImages rewrites the image of every container of the resource pod
+// spec with rewrite. Resources that are not a known workload kind are left
+// untouched.
+func rewriteImages(resource *unstructured.Unstructured, rewrite func(image string) string) {
+	path, ok := podSpecPaths[resource.GroupVersionKind().GroupKind()]
+	if !ok {
+		return
+	}
+	podSpec, found, err := unstructured.NestedMap(resource.Object, path...)
+	if !found || err != nil {
+		return
+	}
+
+	changed := false
+	for _, field := range containerFields {
+		containers, ok := podSpec[field].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, ok := container["image"].(string)
+			if !ok {
+				continue
+			}
+			if rewritten := rewrite(image); rewritten != image {
+				container["image"] = rewritten
+				changed = true
+			}
+		}
+	}
+	if changed {
+		_ = unstructured.SetNestedMap(resource.Object, podSpec, path...)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRewriteImages(t *testing.T) {
+	mirror := func(image string) string {
+		if strings.HasPrefix(image, "registry.internal/") {
+			return image
+		}
+		return "registry.internal/" + image
+	}
+	containers := func(images ...string) []interface{} {
+		var containers []interface{}
+		for _, image := range images {
+			containers = append(containers, map[string]interface{}{"name": "c", "image": image})
+		}
+		return containers
+	}
+
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "deployment containers and init containers",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"initContainers": containers("busybox:1.36"),
+							"containers":     containers("nginx:1.27", "registry.internal/sidecar:v1"),
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"initContainers": containers("registry.internal/busybox:1.36"),
+							"containers":     containers("registry.internal/nginx:1.27", "registry.internal/sidecar:v1"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "cronjob containers",
+			obj: map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "CronJob",
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"containers": containers("backup:v2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "CronJob",
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"containers": containers("registry.internal/backup:v2"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown kinds are left untouched",
+			obj: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Deployment",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": containers("nginx:1.27"),
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Deployment",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": containers("nginx:1.27"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: tt.obj}
+			rewriteImages(obj, mirror)
+			assert.Equal(t, tt.want, obj.Object)
+		})
+	}
+}
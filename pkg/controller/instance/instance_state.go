@@ -47,4 +47,10 @@ type InstanceState struct {
 	ResourceStates map[string]*ResourceState
 	// Any error encountered during reconciliation
 	ReconcileErr error
+	// IDs of the resources recreated during this reconciliation after being
+	// deleted outside of kro
+	Recreated []string
+	// IDs listed in the apply-only annotation of the instance that are not
+	// resources of the graph
+	UnknownApplyOnly []string
 }
@@ -0,0 +1,160 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+var configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// maxManifestsSize is the maximum size of the data of a ConfigMap, as enforced
+// by the API server.
+const maxManifestsSize = 1 << 20
+
+// manifestsConfigMapName returns the name of the ConfigMap holding the
+// resolved manifests of the instance.
+func manifestsConfigMapName(instance metav1.Object) string {
+	return instance.GetName() + "-kro-manifests"
+}
+
+// recordManifest remembers the manifest of the resource, as resolved and
+// about to be applied. Secrets are never recorded: besides their own data,
+// they are the only resources the sensitive instance fields can be rendered
+// into.
+func (igr *instanceGraphReconciler) recordManifest(resourceID string, resource *unstructured.Unstructured) {
+	if isSecret(resource) {
+		return
+	}
+	if igr.manifests == nil {
+		igr.manifests = make(map[string]*unstructured.Unstructured)
+	}
+	igr.manifests[resourceID] = resource.DeepCopy()
+}
+
+// storeManifests stores the manifests resolved during this reconciliation in
+// a ConfigMap owned by the instance, with a YAML document per resource ID.
+// Resources of the graph that weren't resolved, e.g. because a dependency
+// isn't ready yet, keep the manifest stored by a previous reconciliation. The
+// manifests of the resources no longer in the graph are removed.
+func (igr *instanceGraphReconciler) storeManifests(ctx context.Context, instance *unstructured.Unstructured) error {
+	if len(igr.manifests) == 0 {
+		return nil
+	}
+	data := make(map[string]interface{}, len(igr.manifests))
+	for resourceID, manifest := range igr.manifests {
+		document, err := yaml.Marshal(manifest.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest of resource %s: %w", resourceID, err)
+		}
+		data[resourceID+".yaml"] = string(document)
+	}
+
+	namespace := instance.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	rc := igr.client.Resource(configMapGVR).Namespace(namespace)
+	name := manifestsConfigMapName(instance)
+
+	configMap, err := rc.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if err := checkManifestsSize(data); err != nil {
+			return err
+		}
+		configMap = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"data":       data,
+		}}
+		configMap.SetName(name)
+		configMap.SetNamespace(namespace)
+		configMap.SetOwnerReferences([]metav1.OwnerReference{
+			metadata.NewInstanceOwnerReference(instance.GroupVersionKind(), instance.GetName(), instance.GetUID()),
+		})
+		_, err = rc.Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	// The ConfigMap may be one the instance doesn't own, e.g. created by a user
+	// or by another instance with the same name: never overwrite it.
+	if !isOwnedBy(configMap, instance.GetUID()) {
+		return fmt.Errorf("ConfigMap %s/%s isn't owned by the instance, refusing to store the manifests in it",
+			namespace, name)
+	}
+
+	existing, _, _ := unstructured.NestedMap(configMap.Object, "data")
+	for _, resourceID := range igr.runtime.TopologicalOrder() {
+		key := resourceID + ".yaml"
+		if _, ok := data[key]; ok {
+			continue
+		}
+		if document, ok := existing[key]; ok {
+			data[key] = document
+		}
+	}
+	if err := checkManifestsSize(data); err != nil {
+		return err
+	}
+	configMap.Object["data"] = data
+	_, err = rc.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// checkManifestsSize returns an error if the manifests don't fit in a
+// ConfigMap, rather than letting the API server reject it.
+func checkManifestsSize(data map[string]interface{}) error {
+	size := 0
+	for key, document := range data {
+		size += len(key)
+		if document, ok := document.(string); ok {
+			size += len(document)
+		}
+	}
+	if size > maxManifestsSize {
+		return fmt.Errorf("the manifests of the instance take %d bytes, more than the %d bytes a ConfigMap can hold, "+
+			"remove the %s annotation", size, maxManifestsSize, metadata.StoreManifestsAnnotation)
+	}
+	return nil
+}
+
+// isSecret returns true if the object is a core Secret.
+func isSecret(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Secret"
+}
+
+// isOwnedBy returns true if the object has an owner reference to the object
+// with the given UID.
+func isOwnedBy(obj metav1.Object, uid types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
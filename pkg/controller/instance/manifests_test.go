@@ -0,0 +1,186 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+func TestStoreManifests(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetAPIVersion("kro.run/v1alpha1")
+	instance.SetKind("WebApp")
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	instance.SetUID("instance-uid")
+
+	newConfigMap := func(name, value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"data": map[string]interface{}{"value": value},
+		}}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		return obj
+	}
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	rc := client.Resource(configMapGVR).Namespace("default")
+	newReconciler := func(resourceIDs ...string) *instanceGraphReconciler {
+		return &instanceGraphReconciler{
+			log:    logr.Discard(),
+			client: client,
+			runtime: graphRuntime{
+				configMapRuntime: configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}},
+				resourceIDs:      resourceIDs,
+			},
+			reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+		}
+	}
+	storedManifests := func(t *testing.T) map[string]interface{} {
+		stored, err := rc.Get(context.Background(), "my-app-kro-manifests", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []metav1.OwnerReference{
+			metadata.NewInstanceOwnerReference(instance.GroupVersionKind(), "my-app", "instance-uid"),
+		}, stored.GetOwnerReferences())
+
+		data, _, err := unstructured.NestedStringMap(stored.Object, "data")
+		require.NoError(t, err)
+		manifests := map[string]interface{}{}
+		for key, document := range data {
+			var manifest map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(document), &manifest))
+			manifests[key] = manifest
+		}
+		return manifests
+	}
+
+	igr := newReconciler("first", "second")
+	for _, id := range []string{"first", "second"} {
+		err := igr.handleResourceCreation(context.Background(), rc, newConfigMap(id, "v1"), id, &ResourceState{})
+		require.Error(t, err)
+	}
+	require.NoError(t, igr.storeManifests(context.Background(), instance))
+	assert.Equal(t, map[string]interface{}{
+		"first.yaml":  igr.manifests["first"].Object,
+		"second.yaml": igr.manifests["second"].Object,
+	}, storedManifests(t))
+	assert.Equal(t, "first", igr.manifests["first"].GetAnnotations()[metadata.ResourceIDAnnotation])
+
+	// The resources resolved by a later reconciliation replace their stored
+	// manifest, the other ones are kept.
+	previous := igr.manifests["second"].Object
+	igr = newReconciler("first", "second")
+	igr.recordManifest("first", newConfigMap("first", "v2"))
+	require.NoError(t, igr.storeManifests(context.Background(), instance))
+	assert.Equal(t, map[string]interface{}{
+		"first.yaml":  newConfigMap("first", "v2").Object,
+		"second.yaml": previous,
+	}, storedManifests(t))
+
+	// The manifests of the resources removed from the graph are pruned.
+	igr = newReconciler("first")
+	igr.recordManifest("first", newConfigMap("first", "v3"))
+	require.NoError(t, igr.storeManifests(context.Background(), instance))
+	assert.Equal(t, map[string]interface{}{
+		"first.yaml": newConfigMap("first", "v3").Object,
+	}, storedManifests(t))
+
+	// Manifests that don't fit in a ConfigMap are rejected, the stored ones
+	// are left untouched.
+	igr = newReconciler("first")
+	igr.recordManifest("first", newConfigMap("first", strings.Repeat("x", maxManifestsSize)))
+	err := igr.storeManifests(context.Background(), instance)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than the 1048576 bytes a ConfigMap can hold")
+	assert.Equal(t, map[string]interface{}{
+		"first.yaml": newConfigMap("first", "v3").Object,
+	}, storedManifests(t))
+}
+
+func TestStoreManifestsSkipsSecrets(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetAPIVersion("kro.run/v1alpha1")
+	instance.SetKind("WebApp")
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	instance.SetUID("instance-uid")
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"stringData": map[string]interface{}{"password": "hunter2"},
+	}}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName("credentials")
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	igr := &instanceGraphReconciler{client: client}
+	igr.recordManifest("credentials", secret)
+	assert.Empty(t, igr.manifests)
+	require.NoError(t, igr.storeManifests(context.Background(), instance))
+
+	_, err := client.Resource(configMapGVR).Namespace("default").Get(
+		context.Background(), "my-app-kro-manifests", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestStoreManifestsNotOwned(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetAPIVersion("kro.run/v1alpha1")
+	instance.SetKind("WebApp")
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	instance.SetUID("instance-uid")
+
+	foreign := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"config.yaml": "user data"},
+	}}
+	foreign.SetAPIVersion("v1")
+	foreign.SetKind("ConfigMap")
+	foreign.SetNamespace("default")
+	foreign.SetName("my-app-kro-manifests")
+	foreign.SetOwnerReferences([]metav1.OwnerReference{
+		metadata.NewInstanceOwnerReference(instance.GroupVersionKind(), "my-app", "previous-uid"),
+	})
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), foreign)
+	igr := &instanceGraphReconciler{client: client}
+	manifest := &unstructured.Unstructured{}
+	manifest.SetAPIVersion("v1")
+	manifest.SetKind("ConfigMap")
+	manifest.SetName("settings")
+	igr.recordManifest("settings", manifest)
+	require.Error(t, igr.storeManifests(context.Background(), instance))
+
+	stored, err := client.Resource(configMapGVR).Namespace("default").Get(
+		context.Background(), "my-app-kro-manifests", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, foreign.Object["data"], stored.Object["data"])
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+// suspendWorkload suspends the resource if it is a known workload kind:
+// Deployments and StatefulSets are scaled to zero and CronJobs are suspended.
+// The other resources are left untouched.
+//
+// The replicas to restore when the instance is resumed are kept in the
+// SuspendedReplicasAnnotation: the templated replicas if any, otherwise the
+// replicas of the observed workload before it was suspended. observed is nil
+// when the workload is created.
+func suspendWorkload(resource, observed *unstructured.Unstructured) {
+	switch resource.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		metadata.SetAnnotations(resource, map[string]string{
+			metadata.SuspendedReplicasAnnotation: strconv.FormatInt(replicasToRestore(resource, observed), 10),
+		})
+		_ = unstructured.SetNestedField(resource.Object, int64(0), "spec", "replicas")
+	case schema.GroupKind{Group: "batch", Kind: "CronJob"}:
+		_ = unstructured.SetNestedField(resource.Object, true, "spec", "suspend")
+	}
+}
+
+// resumeWorkload undoes the suspension of the resource when its template
+// doesn't set the suspended fields. Otherwise, as only the templated fields
+// are compared with the observed state, the workload would stay suspended:
+// CronJobs are explicitly unsuspended, and Deployments and StatefulSets
+// scaled to zero by kro get back the replicas they had before.
+func resumeWorkload(resource, observed *unstructured.Unstructured) {
+	switch resource.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		if _, ok, _ := unstructured.NestedFieldNoCopy(resource.Object, "spec", "replicas"); ok || observed == nil {
+			return
+		}
+		value, ok := observed.GetAnnotations()[metadata.SuspendedReplicasAnnotation]
+		if !ok {
+			return
+		}
+		replicas, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			replicas = defaultReplicas
+		}
+		_ = unstructured.SetNestedField(resource.Object, replicas, "spec", "replicas")
+	case schema.GroupKind{Group: "batch", Kind: "CronJob"}:
+		if _, ok, _ := unstructured.NestedFieldNoCopy(resource.Object, "spec", "suspend"); !ok {
+			_ = unstructured.SetNestedField(resource.Object, false, "spec", "suspend")
+		}
+	}
+}
+
+// defaultReplicas is the number of replicas of a workload that doesn't set
+// them, as defaulted by the API server.
+const defaultReplicas = int64(1)
+
+// replicasToRestore returns the replicas of the workload before it was
+// suspended.
+func replicasToRestore(resource, observed *unstructured.Unstructured) int64 {
+	if replicas, ok, _ := unstructured.NestedInt64(resource.Object, "spec", "replicas"); ok {
+		return replicas
+	}
+	if observed == nil {
+		return defaultReplicas
+	}
+	// Already suspended, keep the replicas recorded at the suspension.
+	if value, ok := observed.GetAnnotations()[metadata.SuspendedReplicasAnnotation]; ok {
+		if replicas, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return replicas
+		}
+	}
+	if replicas, ok, _ := unstructured.NestedInt64(observed.Object, "spec", "replicas"); ok {
+		return replicas
+	}
+	return defaultReplicas
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+// recordingResourceClient is a resource client recording the objects passed
+// to Update.
+type recordingResourceClient struct {
+	dynamic.ResourceInterface
+	updated []*unstructured.Unstructured
+}
+
+func (c *recordingResourceClient) Update(
+	_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string,
+) (*unstructured.Unstructured, error) {
+	c.updated = append(c.updated, obj)
+	return obj, nil
+}
+
+func TestSuspendWorkload(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		kind       string
+		spec       map[string]interface{}
+		want       map[string]interface{}
+	}{
+		{
+			name:       "deployment scaled to zero",
+			apiVersion: "apps/v1",
+			kind:       "Deployment",
+			spec:       map[string]interface{}{"replicas": int64(3)},
+			want:       map[string]interface{}{"replicas": int64(0)},
+		},
+		{
+			name:       "statefulset without replicas scaled to zero",
+			apiVersion: "apps/v1",
+			kind:       "StatefulSet",
+			spec:       map[string]interface{}{},
+			want:       map[string]interface{}{"replicas": int64(0)},
+		},
+		{
+			name:       "cronjob suspended",
+			apiVersion: "batch/v1",
+			kind:       "CronJob",
+			spec:       map[string]interface{}{"schedule": "@daily"},
+			want:       map[string]interface{}{"schedule": "@daily", "suspend": true},
+		},
+		{
+			name:       "configmap untouched",
+			apiVersion: "v1",
+			kind:       "ConfigMap",
+			spec:       map[string]interface{}{"replicas": int64(3)},
+			want:       map[string]interface{}{"replicas": int64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": tt.apiVersion,
+				"kind":       tt.kind,
+				"spec":       tt.spec,
+			}}
+			suspendWorkload(obj, nil)
+			assert.Equal(t, tt.want, obj.Object["spec"])
+		})
+	}
+}
+
+func TestUpdateResourceSuspend(t *testing.T) {
+	newDeployment := func(replicas int64) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+		obj.SetName("test")
+		obj.SetAnnotations(map[string]string{metadata.ResourceIDAnnotation: "deployment"})
+		_ = unstructured.SetNestedField(obj.Object, replicas, "spec", "replicas")
+		return obj
+	}
+	newReconciler := func(annotations map[string]string) *instanceGraphReconciler {
+		instance := &unstructured.Unstructured{}
+		instance.SetAnnotations(annotations)
+		return &instanceGraphReconciler{
+			log:             logr.Discard(),
+			runtime:         fakeRuntime{instance: instance},
+			reconcileConfig: ReconcileConfig{DefaultRequeueDuration: time.Second},
+			suspended:       metadata.IsSuspended(instance),
+		}
+	}
+
+	t.Run("suspending scales the deployment to zero", func(t *testing.T) {
+		client := &recordingResourceClient{}
+		state := &ResourceState{}
+		err := newReconciler(map[string]string{metadata.SuspendAnnotation: "true"}).updateResource(
+			context.Background(), client, newDeployment(3), newDeployment(3), "deployment", state,
+		)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State)
+		require.Len(t, client.updated, 1)
+		replicas, _, _ := unstructured.NestedInt64(client.updated[0].Object, "spec", "replicas")
+		assert.Equal(t, int64(0), replicas)
+	})
+
+	t.Run("suspended deployment is in sync", func(t *testing.T) {
+		client := &recordingResourceClient{}
+		state := &ResourceState{}
+		observed := newDeployment(0)
+		observed.SetAnnotations(map[string]string{
+			metadata.ResourceIDAnnotation:        "deployment",
+			metadata.SuspendedReplicasAnnotation: "3",
+		})
+		err := newReconciler(map[string]string{metadata.SuspendAnnotation: "true"}).updateResource(
+			context.Background(), client, newDeployment(3), observed, "deployment", state,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ResourceStateSynced, state.State)
+		assert.Empty(t, client.updated)
+	})
+
+	t.Run("resuming restores the templated replicas", func(t *testing.T) {
+		client := &recordingResourceClient{}
+		state := &ResourceState{}
+		err := newReconciler(nil).updateResource(
+			context.Background(), client, newDeployment(3), newDeployment(0), "deployment", state,
+		)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State)
+		require.Len(t, client.updated, 1)
+		replicas, _, _ := unstructured.NestedInt64(client.updated[0].Object, "spec", "replicas")
+		assert.Equal(t, int64(3), replicas)
+	})
+
+	t.Run("suspending records the replicas of a deployment without templated replicas", func(t *testing.T) {
+		client := &recordingResourceClient{}
+		desired := newDeployment(0)
+		unstructured.RemoveNestedField(desired.Object, "spec", "replicas")
+		err := newReconciler(map[string]string{metadata.SuspendAnnotation: "true"}).updateResource(
+			context.Background(), client, desired, newDeployment(4), "deployment", &ResourceState{},
+		)
+		require.Error(t, err)
+		require.Len(t, client.updated, 1)
+		replicas, _, _ := unstructured.NestedInt64(client.updated[0].Object, "spec", "replicas")
+		assert.Equal(t, int64(0), replicas)
+		assert.Equal(t, "4", client.updated[0].GetAnnotations()[metadata.SuspendedReplicasAnnotation])
+	})
+
+	t.Run("resuming restores the replicas of a deployment without templated replicas", func(t *testing.T) {
+		client := &recordingResourceClient{}
+		desired := newDeployment(0)
+		unstructured.RemoveNestedField(desired.Object, "spec", "replicas")
+		observed := newDeployment(0)
+		observed.SetAnnotations(map[string]string{
+			metadata.ResourceIDAnnotation:        "deployment",
+			metadata.SuspendedReplicasAnnotation: "4",
+		})
+		state := &ResourceState{}
+		err := newReconciler(nil).updateResource(
+			context.Background(), client, desired, observed, "deployment", state,
+		)
+		require.Error(t, err)
+		assert.Equal(t, ResourceStateUpdating, state.State)
+		require.Len(t, client.updated, 1)
+		replicas, _, _ := unstructured.NestedInt64(client.updated[0].Object, "spec", "replicas")
+		assert.Equal(t, int64(4), replicas)
+		assert.NotContains(t, client.updated[0].GetAnnotations(), metadata.SuspendedReplicasAnnotation)
+	})
+
+	t.Run("resuming a cronjob without templated suspend", func(t *testing.T) {
+		newCronJob := func() *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("batch/v1")
+			obj.SetKind("CronJob")
+			obj.SetName("test")
+			obj.SetAnnotations(map[string]string{metadata.ResourceIDAnnotation: "cronjob"})
+			_ = unstructured.SetNestedField(obj.Object, "@daily", "spec", "schedule")
+			return obj
+		}
+		observed := newCronJob()
+		_ = unstructured.SetNestedField(observed.Object, true, "spec", "suspend")
+
+		client := &recordingResourceClient{}
+		err := newReconciler(nil).updateResource(
+			context.Background(), client, newCronJob(), observed, "cronjob", &ResourceState{},
+		)
+		require.Error(t, err)
+		require.Len(t, client.updated, 1)
+		suspend, found, _ := unstructured.NestedBool(client.updated[0].Object, "spec", "suspend")
+		assert.True(t, found)
+		assert.False(t, suspend)
+	})
+}
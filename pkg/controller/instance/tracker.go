@@ -0,0 +1,86 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// observedResources remembers the resources observed in the cluster by the
+// previous reconciliations, to tell the resources created for the first time
+// apart from the ones recreated after being deleted outside of kro. The
+// resources are kept in memory: a controller restart forgets them.
+//
+// A nil *observedResources tracks nothing.
+type observedResources struct {
+	mu sync.Mutex
+	// resources holds the observed resources of each instance.
+	resources map[types.UID]map[observedResourceKey]struct{}
+}
+
+// observedResourceKey identifies a resource of an instance. The name is part
+// of the key, renaming a resource isn't a deletion.
+type observedResourceKey struct {
+	id   string
+	name string
+}
+
+func newObservedResources() *observedResources {
+	return &observedResources{resources: make(map[types.UID]map[observedResourceKey]struct{})}
+}
+
+func newObservedResourceKey(resourceID string, resource *unstructured.Unstructured) observedResourceKey {
+	return observedResourceKey{id: resourceID, name: resource.GetName()}
+}
+
+// observe records that the resource of the instance exists.
+func (o *observedResources) observe(instance metav1.Object, resourceID string, resource *unstructured.Unstructured) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	uid := instance.GetUID()
+	if o.resources[uid] == nil {
+		o.resources[uid] = make(map[observedResourceKey]struct{})
+	}
+	o.resources[uid][newObservedResourceKey(resourceID, resource)] = struct{}{}
+}
+
+// observed returns true if the resource of the instance existed during a
+// previous reconciliation.
+func (o *observedResources) observed(instance metav1.Object, resourceID string, resource *unstructured.Unstructured) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.resources[instance.GetUID()][newObservedResourceKey(resourceID, resource)]
+	return ok
+}
+
+// forget drops the resources of the instance.
+func (o *observedResources) forget(instance metav1.Object) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.resources, instance.GetUID())
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// configMapRuntime is a runtime with a single, resolved, config map.
+type configMapRuntime struct {
+	fakeRuntime
+	configMap *unstructured.Unstructured
+}
+
+func (r configMapRuntime) GetResource(string) (*unstructured.Unstructured, runtime.ResourceState) {
+	return r.configMap.DeepCopy(), runtime.ResourceStateResolved
+}
+
+func (configMapRuntime) ResourceDescriptor(string) runtime.ResourceDescriptor {
+	return configMapDescriptor{}
+}
+
+type configMapDescriptor struct {
+	fakeDescriptor
+}
+
+func (configMapDescriptor) IsNamespaced() bool {
+	return true
+}
+
+func (configMapDescriptor) IsExternalRef() bool {
+	return false
+}
+
+func (configMapDescriptor) ReconcileOnce() bool {
+	return false
+}
+
+func (configMapDescriptor) DeletionPropagation() metav1.DeletionPropagation {
+	return ""
+}
+
+func TestObservedResources(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetNamespace("default")
+	configMap.SetName("config")
+	renamed := configMap.DeepCopy()
+	renamed.SetName("renamed")
+	instance := &metav1.ObjectMeta{UID: "uid"}
+	other := &metav1.ObjectMeta{UID: "other-uid"}
+
+	o := newObservedResources()
+	assert.False(t, o.observed(instance, "configmap", configMap))
+	o.observe(instance, "configmap", configMap)
+	assert.True(t, o.observed(instance, "configmap", configMap))
+	assert.False(t, o.observed(other, "configmap", configMap))
+	assert.False(t, o.observed(instance, "configmap", renamed))
+	o.forget(instance)
+	assert.False(t, o.observed(instance, "configmap", configMap))
+
+	var nilTracker *observedResources
+	nilTracker.observe(instance, "configmap", configMap)
+	assert.False(t, nilTracker.observed(instance, "configmap", configMap))
+}
+
+func TestRecreatedResourceDetection(t *testing.T) {
+	instance := &unstructured.Unstructured{}
+	instance.SetNamespace("default")
+	instance.SetName("my-app")
+	instance.SetUID("uid")
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("config")
+
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	gvr := fakeDescriptor{}.GetGroupVersionResource()
+	tracker := newObservedResources()
+	reconcile := func() *instanceGraphReconciler {
+		igr := &instanceGraphReconciler{
+			log:               logr.Discard(),
+			client:            client,
+			runtime:           configMapRuntime{fakeRuntime: fakeRuntime{instance: instance}, configMap: configMap},
+			reconcileConfig:   ReconcileConfig{DefaultRequeueDuration: time.Second},
+			observedResources: tracker,
+			state:             newInstanceState(),
+		}
+		resource, _ := igr.runtime.GetResource("configmap")
+		err := igr.handleResourceReconciliation(context.Background(), "configmap", resource, &ResourceState{})
+		require.Error(t, err)
+		return igr
+	}
+
+	// The first reconciliation creates the config map.
+	igr := reconcile()
+	assert.Empty(t, igr.state.Recreated)
+	_, err := client.Resource(gvr).Namespace("default").Get(context.Background(), "config", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// The config map is deleted outside of kro, the next reconciliation
+	// recreates it and reports it.
+	require.NoError(t, client.Resource(gvr).Namespace("default").Delete(
+		context.Background(), "config", metav1.DeleteOptions{},
+	))
+	igr = reconcile()
+	assert.Equal(t, []string{"configmap"}, igr.state.Recreated)
+
+	conditions := igr.prepareStatus()["conditions"].([]interface{})
+	require.Len(t, conditions, 2)
+	recreated := conditions[1].(map[string]interface{})
+	assert.Equal(t, ConditionResourcesRecreated, recreated["type"])
+	assert.Equal(t, ReasonDeletedExternally, recreated["reason"])
+	assert.Equal(t, "resources deleted outside of kro were recreated: configmap", recreated["message"])
+}
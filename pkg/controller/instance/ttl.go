@@ -0,0 +1,63 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kro-run/kro/pkg/requeue"
+)
+
+// deleteExpiredInstance deletes an instance whose TTL expired. The deletion is
+// conditioned on the instance uid, so that a recreated instance with the same
+// name is left alone.
+func deleteExpiredInstance(ctx context.Context, client dynamic.ResourceInterface, instance *unstructured.Unstructured) error {
+	uid := instance.GetUID()
+	err := client.Delete(ctx, instance.GetName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete expired instance: %w", err)
+	}
+	return nil
+}
+
+// requeueBeforeExpiry makes sure the instance is reconciled again once its TTL
+// expires: the reconciliation result is turned into a delayed requeue, unless
+// it already requeues earlier than that.
+func requeueBeforeExpiry(err error, remaining time.Duration) error {
+	var requeueAfter *requeue.RequeueNeededAfter
+	switch {
+	case err == nil:
+		return requeue.NeededAfter(nil, remaining)
+	case errors.As(err, &requeueAfter):
+		if requeueAfter.Duration() > remaining {
+			return requeue.NeededAfter(requeueAfter.Unwrap(), remaining)
+		}
+		return err
+	default:
+		// Errors are retried with rate limiting, which happens before the
+		// expiry in practice.
+		return err
+	}
+}
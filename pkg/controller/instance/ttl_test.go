@@ -0,0 +1,91 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kroclient "github.com/kro-run/kro/pkg/client"
+	"github.com/kro-run/kro/pkg/graph"
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/requeue"
+)
+
+// dynamicClientSet is a client set only providing a dynamic client.
+type dynamicClientSet struct {
+	kroclient.SetInterface
+	dynamic dynamic.Interface
+}
+
+func (s *dynamicClientSet) Dynamic() dynamic.Interface {
+	return s.dynamic
+}
+
+func TestReconcileDeletesExpiredInstance(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"}
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "WebApp",
+		"metadata": map[string]interface{}{
+			"name": "my-app",
+			"annotations": map[string]interface{}{
+				metadata.TTLAnnotation: time.Now().Add(-time.Minute).Format(time.RFC3339),
+			},
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(k8sruntime.NewScheme(), instance)
+	// The instance is deleted before its graph is needed, only the scope of
+	// the instances is: a zero graph resource is cluster scoped.
+	rgd := &graph.Graph{Instance: &graph.Resource{}}
+	c := NewController(logr.Discard(), ReconcileConfig{}, gvr, rgd, 0, &dynamicClientSet{dynamic: client},
+		nil, nil, metadata.GenericLabeler{})
+
+	err := c.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "my-app"}})
+	require.NoError(t, err)
+
+	_, err = client.Resource(gvr).Get(context.Background(), "my-app", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the instance to be deleted, got %v", err)
+}
+
+func TestRequeueBeforeExpiry(t *testing.T) {
+	durationOf := func(t *testing.T, err error) time.Duration {
+		var requeueAfter *requeue.RequeueNeededAfter
+		require.True(t, errors.As(err, &requeueAfter))
+		return requeueAfter.Duration()
+	}
+
+	assert.Equal(t, time.Hour, durationOf(t, requeueBeforeExpiry(nil, time.Hour)))
+	assert.Equal(t, time.Minute, durationOf(t, requeueBeforeExpiry(requeue.NeededAfter(nil, time.Minute), time.Hour)))
+	assert.Equal(t, time.Second, durationOf(t, requeueBeforeExpiry(requeue.NeededAfter(nil, time.Minute), time.Second)))
+
+	err := errors.New("failed")
+	assert.Equal(t, err, requeueBeforeExpiry(err, time.Hour))
+}
@@ -32,6 +32,7 @@ import (
 
 	"github.com/kro-run/kro/api/v1alpha1"
 	kroclient "github.com/kro-run/kro/pkg/client"
+	instancectrl "github.com/kro-run/kro/pkg/controller/instance"
 	"github.com/kro-run/kro/pkg/dynamiccontroller"
 	"github.com/kro-run/kro/pkg/graph"
 	"github.com/kro-run/kro/pkg/metadata"
@@ -57,6 +58,9 @@ type ResourceGraphDefinitionReconciler struct {
 	rgBuilder               *graph.Builder
 	dynamicController       *dynamiccontroller.DynamicController
 	maxConcurrentReconciles int
+	// reconcileConfig is the configuration passed down to the instance
+	// controllers.
+	reconcileConfig instancectrl.ReconcileConfig
 }
 
 func NewResourceGraphDefinitionReconciler(
@@ -65,6 +69,7 @@ func NewResourceGraphDefinitionReconciler(
 	dynamicController *dynamiccontroller.DynamicController,
 	builder *graph.Builder,
 	maxConcurrentReconciles int,
+	reconcileConfig instancectrl.ReconcileConfig,
 ) *ResourceGraphDefinitionReconciler {
 	crdWrapper := clientSet.CRD(kroclient.CRDWrapperConfig{})
 
@@ -76,6 +81,7 @@ func NewResourceGraphDefinitionReconciler(
 		metadataLabeler:         metadata.NewKROMetaLabeler(),
 		rgBuilder:               builder,
 		maxConcurrentReconciles: maxConcurrentReconciles,
+		reconcileConfig:         reconcileConfig,
 	}
 }
 
@@ -121,6 +127,23 @@ func (r *ResourceGraphDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) e
 				},
 			}),
 		).
+		// A resource graph definition that lost a CRD conflict takes the
+		// kind over once its owner is gone.
+		Watches(
+			&v1alpha1.ResourceGraphDefinition{},
+			handler.EnqueueRequestsFromMapFunc(r.findRGDsForKind),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return false
+				},
+				CreateFunc: func(e event.CreateEvent) bool {
+					return false
+				},
+				DeleteFunc: func(e event.DeleteEvent) bool {
+					return true
+				},
+			}),
+		).
 		Complete(reconcile.AsReconciler[*v1alpha1.ResourceGraphDefinition](mgr.GetClient(), r))
 }
 
@@ -152,6 +175,38 @@ func (r *ResourceGraphDefinitionReconciler) findRGDsForCRD(ctx context.Context,
 	}
 }
 
+// findRGDsForKind returns a list of reconcile requests for the other
+// ResourceGraphDefinitions declaring the same kind as the given one. It is used
+// to let them claim the kind when the given ResourceGraphDefinition is deleted.
+func (r *ResourceGraphDefinitionReconciler) findRGDsForKind(ctx context.Context, obj client.Object) []reconcile.Request {
+	rgd, ok := obj.(*v1alpha1.ResourceGraphDefinition)
+	if !ok || rgd.Spec.Schema == nil {
+		return nil
+	}
+
+	var rgds v1alpha1.ResourceGraphDefinitionList
+	if err := r.List(ctx, &rgds); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list resource graph definitions")
+		return nil
+	}
+
+	group, kind := schemaGroupKind(rgd)
+	var requests []reconcile.Request
+	for i := range rgds.Items {
+		other := &rgds.Items[i]
+		if other.Name == rgd.Name || other.Spec.Schema == nil {
+			continue
+		}
+		if otherGroup, otherKind := schemaGroupKind(other); otherGroup != group || otherKind != kind {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: other.Name},
+		})
+	}
+	return requests
+}
+
 func (r *ResourceGraphDefinitionReconciler) Reconcile(ctx context.Context, o *v1alpha1.ResourceGraphDefinition) (ctrl.Result, error) {
 	if !o.DeletionTimestamp.IsZero() {
 		if err := r.cleanupResourceGraphDefinition(ctx, o); err != nil {
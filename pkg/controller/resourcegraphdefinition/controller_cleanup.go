@@ -34,18 +34,25 @@ import (
 func (r *ResourceGraphDefinitionReconciler) cleanupResourceGraphDefinition(ctx context.Context, rgd *v1alpha1.ResourceGraphDefinition) error {
 	ctrl.LoggerFrom(ctx).V(1).Info("cleaning up resource graph definition", "name", rgd.Name)
 
+	// The microcontroller and the CRD belong to another resource graph
+	// definition declaring the same kind, leave them alone.
+	owner, err := r.findConflictingResourceGraphDefinition(ctx, rgd)
+	if err != nil {
+		return err
+	}
+	if owner != "" {
+		ctrl.LoggerFrom(ctx).Info("skipping cleanup, kind is owned by another resource graph definition", "owner", owner)
+		return nil
+	}
+
 	// shutdown microcontroller
 	gvr := metadata.GetResourceGraphDefinitionInstanceGVR(rgd.Spec.Schema.Group, rgd.Spec.Schema.APIVersion, rgd.Spec.Schema.Kind)
 	if err := r.shutdownResourceGraphDefinitionMicroController(ctx, &gvr); err != nil {
 		return fmt.Errorf("failed to shutdown microcontroller: %w", err)
 	}
 
-	group := rgd.Spec.Schema.Group
-	if group == "" {
-		group = v1alpha1.KRODomainName
-	}
 	// cleanup CRD
-	crdName := extractCRDName(group, rgd.Spec.Schema.Kind)
+	crdName := extractCRDName(schemaGroupKind(rgd))
 	if err := r.cleanupResourceGraphDefinitionCRD(ctx, crdName); err != nil {
 		return fmt.Errorf("failed to cleanup CRD %s: %w", crdName, err)
 	}
@@ -17,13 +17,13 @@ package resourcegraphdefinition
 import (
 	"context"
 	"fmt"
-	"time"
 
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/kro-run/kro/api/v1alpha1"
+	kroclient "github.com/kro-run/kro/pkg/client"
 	instancectrl "github.com/kro-run/kro/pkg/controller/instance"
 	"github.com/kro-run/kro/pkg/dynamiccontroller"
 	"github.com/kro-run/kro/pkg/graph"
@@ -46,6 +46,9 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(ctx
 		return nil, nil, err
 	}
 	mark.ResourceGraphValid()
+	for _, warning := range processedRGD.Warnings {
+		log.Info("resource graph definition warning", "warning", warning)
+	}
 
 	// Setup metadata labeling
 	graphExecLabeler, err := r.setupLabeler(rgd)
@@ -54,6 +57,19 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(ctx
 		return nil, nil, fmt.Errorf("failed to setup labeler: %w", err)
 	}
 
+	// Make sure no other resource graph definition owns the same kind
+	owner, err := r.findConflictingResourceGraphDefinition(ctx, rgd)
+	if err != nil {
+		mark.KindUnready(err.Error())
+		return processedRGD.TopologicalOrder, resourcesInfo, newCRDError(err)
+	}
+	if owner != "" {
+		mark.KindConflict(owner)
+		return processedRGD.TopologicalOrder, resourcesInfo, newCRDError(
+			fmt.Errorf("kind %s is already owned by resource graph definition %s", rgd.Spec.Schema.Kind, owner),
+		)
+	}
+
 	crd := processedRGD.Instance.GetCRD()
 	graphExecLabeler.ApplyLabels(&crd.ObjectMeta)
 
@@ -65,13 +81,19 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(ctx
 	}
 	if crd, err = r.crdManager.Get(ctx, crd.Name); err != nil {
 		mark.KindUnready(err.Error())
+	} else if !kroclient.IsEstablished(crd) {
+		// Don't report the kind as ready until the API server serves it, the
+		// returned error requeues the RGD until the CRD is established.
+		err = fmt.Errorf("CRD %s is not established yet", crd.Name)
+		mark.KindUnready(err.Error())
+		return processedRGD.TopologicalOrder, resourcesInfo, newCRDError(err)
 	} else {
 		mark.KindReady(crd.Status.AcceptedNames.Kind)
 	}
 
 	// Setup and start microcontroller
 	gvr := processedRGD.Instance.GetGroupVersionResource()
-	controller := r.setupMicroController(gvr, processedRGD, rgd.Spec.DefaultServiceAccounts, graphExecLabeler)
+	controller := r.setupMicroController(gvr, processedRGD, rgd.Generation, rgd.Spec.DefaultServiceAccounts, rgd.Spec.Propagate, graphExecLabeler)
 
 	log.V(1).Info("reconciling resource graph definition micro controller")
 	// TODO: the context that is passed here is tied to the reconciliation of the rgd, we might need to make
@@ -96,7 +118,9 @@ func (r *ResourceGraphDefinitionReconciler) setupLabeler(rgd *v1alpha1.ResourceG
 func (r *ResourceGraphDefinitionReconciler) setupMicroController(
 	gvr schema.GroupVersionResource,
 	processedRGD *graph.Graph,
+	rgdGeneration int64,
 	defaultSVCs map[string]string,
+	propagation *v1alpha1.Propagation,
 	labeler metadata.Labeler,
 ) *instancectrl.Controller {
 	instanceLogger := r.instanceLogger.WithName(fmt.Sprintf("%s-controller", gvr.Resource)).WithValues(
@@ -107,15 +131,13 @@ func (r *ResourceGraphDefinitionReconciler) setupMicroController(
 
 	return instancectrl.NewController(
 		instanceLogger,
-		instancectrl.ReconcileConfig{
-			DefaultRequeueDuration:    3 * time.Second,
-			DeletionGraceTimeDuration: 30 * time.Second,
-			DeletionPolicy:            "Delete",
-		},
+		r.reconcileConfig,
 		gvr,
 		processedRGD,
+		rgdGeneration,
 		r.clientSet,
 		defaultSVCs,
+		propagation,
 		labeler,
 	)
 }
@@ -151,6 +173,51 @@ func buildResourceInfo(name string, deps []string) v1alpha1.ResourceInformation
 	}
 }
 
+// findConflictingResourceGraphDefinition returns the name of another resource
+// graph definition declaring the same group and kind, that owns the generated
+// CRD. The oldest resource graph definition owns the CRD, ties are broken by
+// name. An empty string is returned if there is no conflict.
+func (r *ResourceGraphDefinitionReconciler) findConflictingResourceGraphDefinition(ctx context.Context, rgd *v1alpha1.ResourceGraphDefinition) (string, error) {
+	var rgds v1alpha1.ResourceGraphDefinitionList
+	if err := r.List(ctx, &rgds); err != nil {
+		return "", fmt.Errorf("failed to list resource graph definitions: %w", err)
+	}
+
+	group, kind := schemaGroupKind(rgd)
+	for i := range rgds.Items {
+		other := &rgds.Items[i]
+		if other.Name == rgd.Name || !other.DeletionTimestamp.IsZero() || other.Spec.Schema == nil {
+			continue
+		}
+		if otherGroup, otherKind := schemaGroupKind(other); otherGroup != group || otherKind != kind {
+			continue
+		}
+		if ownsKindBefore(other, rgd) {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// schemaGroupKind returns the group and kind of the CRD generated for the
+// resource graph definition.
+func schemaGroupKind(rgd *v1alpha1.ResourceGraphDefinition) (string, string) {
+	group := rgd.Spec.Schema.Group
+	if group == "" {
+		group = v1alpha1.KRODomainName
+	}
+	return group, rgd.Spec.Schema.Kind
+}
+
+// ownsKindBefore returns true if a takes precedence over b when both declare
+// the same kind.
+func ownsKindBefore(a, b *v1alpha1.ResourceGraphDefinition) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
 // reconcileResourceGraphDefinitionCRD ensures the CRD is present and up to date in the cluster
 func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionCRD(ctx context.Context, crd *v1.CustomResourceDefinition) error {
 	if err := r.crdManager.Ensure(ctx, *crd); err != nil {
@@ -152,6 +152,11 @@ func (m *ConditionsMarker) KindUnready(msg string) {
 	m.cs.SetFalse(KindReady, "Failed", msg)
 }
 
+// KindConflict signals the kind is already owned by another ResourceGraphDefinition.
+func (m *ConditionsMarker) KindConflict(owner string) {
+	m.cs.SetFalse(KindReady, "CRDConflict", fmt.Sprintf("kind is already owned by resource graph definition %s", owner))
+}
+
 // TODO: it would be nice to know if the Kind was not accepted at all OR if a CRD exists.
 
 // KindReady signals the CustomResourceDefinition has been synced and is ready.
@@ -59,6 +59,7 @@ package dynamiccontroller
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -380,6 +381,27 @@ func (dc *DynamicController) updateFunc(old, new interface{}) {
 		return
 	}
 
+	// Users can force a reconcile without touching the spec by bumping the
+	// reconcile annotation.
+	if metadata.ReconcileRequested(oldObj, newObj) {
+		dc.log.V(1).Info("Reconcile requested through annotation",
+			"name", newObj.GetName(),
+			"namespace", newObj.GetNamespace())
+		dc.enqueueObject(new, "update")
+		return
+	}
+
+	// Metadata changes don't bump the generation, but the kro.run/
+	// annotations, e.g. the suspend one, and the labels propagated to the
+	// resources change how the object is reconciled.
+	if metadata.KROAnnotationsChanged(oldObj, newObj) || !maps.Equal(oldObj.GetLabels(), newObj.GetLabels()) {
+		dc.log.V(1).Info("Reconciling after a metadata change",
+			"name", newObj.GetName(),
+			"namespace", newObj.GetNamespace())
+		dc.enqueueObject(new, "update")
+		return
+	}
+
 	if newObj.GetGeneration() == oldObj.GetGeneration() {
 		dc.log.V(2).Info("Skipping update due to unchanged generation",
 			"name", newObj.GetName(),
@@ -32,6 +32,8 @@ import (
 	"k8s.io/client-go/dynamic/fake"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kro-run/kro/pkg/metadata"
 )
 
 // NOTE(a-hilaly): I'm just playing around with the dynamic controller code here
@@ -213,3 +215,99 @@ func TestInstanceUpdatePolicy(t *testing.T) {
 		assert.True(t, ok)
 	}
 }
+
+func TestUpdateFuncReconcileAnnotation(t *testing.T) {
+	logger := noopLogger()
+	client := setupFakeClient()
+
+	dc := NewDynamicController(logger, Config{}, client)
+
+	oldObj := &unstructured.Unstructured{}
+	oldObj.SetGroupVersionKind(schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"})
+	oldObj.SetName("test-object")
+	oldObj.SetNamespace("default")
+	oldObj.SetGeneration(1)
+
+	// Same generation, no annotation change: nothing to do.
+	dc.updateFunc(oldObj, oldObj.DeepCopy())
+	assert.Equal(t, 0, dc.queue.Len())
+
+	// Same generation, reconcile annotation set: the object is enqueued.
+	newObj := oldObj.DeepCopy()
+	newObj.SetAnnotations(map[string]string{metadata.ReconcileAnnotation: "2025-01-01T00:00:00Z"})
+	dc.updateFunc(oldObj, newObj)
+	require.Equal(t, 1, dc.queue.Len())
+
+	item, _ := dc.queue.Get()
+	assert.Equal(t, "default/test-object", item.NamespacedKey)
+	dc.queue.Done(item)
+	dc.queue.Forget(item)
+
+	// Unchanged annotation value doesn't trigger another reconcile.
+	dc.updateFunc(newObj, newObj.DeepCopy())
+	assert.Equal(t, 0, dc.queue.Len())
+}
+
+func TestUpdateFuncMetadataChanges(t *testing.T) {
+	logger := noopLogger()
+	client := setupFakeClient()
+
+	dc := NewDynamicController(logger, Config{}, client)
+
+	instance := &unstructured.Unstructured{}
+	instance.SetGroupVersionKind(schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"})
+	instance.SetName("test-object")
+	instance.SetNamespace("default")
+	instance.SetGeneration(1)
+
+	tests := []struct {
+		name        string
+		update      func(obj *unstructured.Unstructured)
+		wantEnqueue bool
+	}{
+		{
+			name: "suspend annotation set",
+			update: func(obj *unstructured.Unstructured) {
+				obj.SetAnnotations(map[string]string{metadata.SuspendAnnotation: "true"})
+			},
+			wantEnqueue: true,
+		},
+		{
+			name: "label changed",
+			update: func(obj *unstructured.Unstructured) {
+				obj.SetLabels(map[string]string{"team": "platform"})
+			},
+			wantEnqueue: true,
+		},
+		{
+			name: "other annotation changed",
+			update: func(obj *unstructured.Unstructured) {
+				obj.SetAnnotations(map[string]string{"example.com/note": "updated"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newObj := instance.DeepCopy()
+			tt.update(newObj)
+			dc.updateFunc(instance, newObj)
+			if !tt.wantEnqueue {
+				assert.Equal(t, 0, dc.queue.Len())
+				return
+			}
+			require.Equal(t, 1, dc.queue.Len())
+			item, _ := dc.queue.Get()
+			assert.Equal(t, "default/test-object", item.NamespacedKey)
+			dc.queue.Done(item)
+			dc.queue.Forget(item)
+
+			// Flipping it back is picked up as well, without a generation bump.
+			dc.updateFunc(newObj, instance.DeepCopy())
+			assert.Equal(t, 1, dc.queue.Len())
+			item, _ = dc.queue.Get()
+			dc.queue.Done(item)
+			dc.queue.Forget(item)
+		})
+	}
+}
@@ -17,6 +17,7 @@ package graph
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types/ref"
@@ -25,9 +26,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	apiservercel "k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/openapi/resolver"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/jsonpath"
 
 	"github.com/kro-run/kro/api/v1alpha1"
 	krocel "github.com/kro-run/kro/pkg/cel"
@@ -42,9 +45,22 @@ import (
 	"github.com/kro-run/kro/pkg/simpleschema"
 )
 
+// BuilderOption configures a Builder.
+type BuilderOption func(*Builder)
+
+// WithLenientSchemaMarkers makes the Builder ignore the unknown SimpleSchema
+// markers of the instance schema, instead of rejecting the resource graph
+// definition. The ignored markers are reported in the Graph warnings.
+func WithLenientSchemaMarkers() BuilderOption {
+	return func(b *Builder) {
+		b.lenientSchemaMarkers = true
+	}
+}
+
 // NewBuilder creates a new GraphBuilder instance.
 func NewBuilder(
 	clientConfig *rest.Config,
+	opts ...BuilderOption,
 ) (*Builder, error) {
 	schemaResolver, dc, err := schema.NewCombinedResolver(clientConfig)
 	if err != nil {
@@ -58,6 +74,9 @@ func NewBuilder(
 		schemaResolver:   schemaResolver,
 		discoveryClient:  dc,
 	}
+	for _, opt := range opts {
+		opt(rgBuilder)
+	}
 	return rgBuilder, nil
 }
 
@@ -95,6 +114,9 @@ type Builder struct {
 	// validate the CEL expressions. To revisit.
 	resourceEmulator *emulator.Emulator
 	discoveryClient  discovery.DiscoveryInterface
+	// lenientSchemaMarkers makes the unknown SimpleSchema markers warnings
+	// instead of errors.
+	lenientSchemaMarkers bool
 }
 
 // NewResourceGraphDefinition creates a new ResourceGraphDefinition object from the given ResourceGraphDefinition
@@ -106,6 +128,11 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	// original object.
 	rgd := originalCR.DeepCopy()
 
+	// The overlays are built separately, as a graph per variant, from the
+	// resource graph definition without them.
+	overlays := rgd.Spec.Overlays
+	rgd.Spec.Overlays = nil
+
 	// There are a few steps to build a resource graph definition:
 	// 1. Validate the naming convention of the resource graph definition and its resources.
 	//    kro leverages CEL expressions to allow users to define new types and
@@ -195,6 +222,18 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	// 3. Validate them against the resources defined in the resource graph definition.
 	// 4. Infer the status schema based on the CEL expressions.
 
+	var warnings []string
+	var sensitiveFields []string
+	schemaOpts := []simpleschema.Option{
+		simpleschema.WithSensitiveFields(func(path string) {
+			sensitiveFields = append(sensitiveFields, path)
+		}),
+	}
+	if b.lenientSchemaMarkers {
+		schemaOpts = append(schemaOpts, simpleschema.WithLenientMarkers(func(message string) {
+			warnings = append(warnings, message)
+		}))
+	}
 	instance, err := b.buildInstanceResource(
 		rgd.Spec.Schema.Group,
 		rgd.Spec.Schema.APIVersion,
@@ -203,6 +242,7 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		// We need to pass the resources to the instance resource, so we can validate
 		// the CEL expressions in the context of the resources.
 		resources,
+		schemaOpts...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build resourcegraphdefinition '%v': %w", rgd.Name, err)
@@ -219,6 +259,12 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		return nil, fmt.Errorf("failed to validate resource CEL expressions: %w", err)
 	}
 
+	// Sensitive spec fields may only be rendered into Secrets.
+	err = validateSensitiveReferences(resources, instance, sensitiveFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate sensitive fields: %w", err)
+	}
+
 	// Now that we have the instance resource, we can move into the next stage of
 	// building the resource graph definition. Understanding the relationships between the
 	// resources in the resource graph definition a.k.a the dependency graph.
@@ -241,15 +287,120 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		return nil, fmt.Errorf("failed to get topological order: %w", err)
 	}
 
+	if err := validateReadyGate(rgd.Spec.ReadyGate, resources); err != nil {
+		return nil, fmt.Errorf("failed to validate ready gate: %w", err)
+	}
+
+	readyExpression, readyExpressionDependencies, err := buildReadyExpression(rgd.Spec.ReadyExpression, resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate ready expression: %w", err)
+	}
+
+	for _, rgResource := range rgd.Spec.Resources {
+		warnings = append(warnings, propagationConflicts(rgd.Spec.Propagate, resources[rgResource.ID])...)
+	}
+
+	variants, err := b.buildOverlays(rgd, overlays, instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build overlays: %w", err)
+	}
+
 	resourceGraphDefinition := &Graph{
 		DAG:              dag,
 		Instance:         instance,
 		Resources:        resources,
 		TopologicalOrder: topologicalOrder,
+		ReadyGate:        rgd.Spec.ReadyGate,
+		ReadyExpression:  readyExpression,
+		Propagation:      rgd.Spec.Propagate,
+		Warnings:         warnings,
+
+		readyExpressionDependencies: readyExpressionDependencies,
+		overlays:                    variants,
+	}
+	if overlays != nil {
+		resourceGraphDefinition.overlayField = overlays.Field
 	}
 	return resourceGraphDefinition, nil
 }
 
+// validateReadyGate ensures that every resource ID of the ready gate refers to
+// a resource of the resource graph definition.
+func validateReadyGate(readyGate []string, resources map[string]*Resource) error {
+	for _, id := range readyGate {
+		if _, ok := resources[id]; !ok {
+			return fmt.Errorf("resource %q is not defined in the resource graph definition", id)
+		}
+	}
+	return nil
+}
+
+// buildReadyExpression validates the ready expression of the resource graph
+// definition, and returns it with '${}' removed along with the resources it
+// depends on. The expression must refer to at least one resource and evaluate
+// to a bool.
+func buildReadyExpression(readyExpression string, resources map[string]*Resource) (string, []string, error) {
+	if readyExpression == "" {
+		return "", nil, nil
+	}
+	expressions, err := parser.ParseConditionExpressions([]string{readyExpression})
+	if err != nil {
+		return "", nil, err
+	}
+	expression := expressions[0]
+
+	resourceIDs := maps.Keys(resources)
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceIDs))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	dependencies, isStatic, err := extractDependencies(env, expression, resourceIDs)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to extract dependencies: %w", err)
+	}
+	if isStatic {
+		return "", nil, fmt.Errorf("ready expression %s must refer to a resource", expression)
+	}
+	output, err := ensureExpression(env, expression, resourceIDs, resources)
+	if err != nil {
+		return "", nil, err
+	}
+	if !krocel.IsBoolType(output) {
+		return "", nil, fmt.Errorf("output of ready expression %s can only be of type bool", expression)
+	}
+	return expression, dependencies, nil
+}
+
+// propagationConflicts returns a warning for every propagated label or
+// annotation the resource template also sets, stating which value takes
+// precedence. Labels and annotations set by an expression returning the whole
+// map can't be checked.
+func propagationConflicts(propagation *v1alpha1.Propagation, resource *Resource) []string {
+	if propagation == nil || resource.isExternalRef {
+		return nil
+	}
+	winner := "instance"
+	if propagation.Precedence == v1alpha1.PropagationPrecedenceTemplate {
+		winner = "template"
+	}
+
+	var warnings []string
+	check := func(field, name string, keys []string) {
+		values, _, _ := unstructured.NestedMap(resource.originalObject.Object, "metadata", field)
+		for _, key := range keys {
+			if _, ok := values[key]; ok {
+				warnings = append(warnings, fmt.Sprintf(
+					"resource %q sets the propagated %s %q: the %s value takes precedence",
+					resource.id, name, key, winner,
+				))
+			}
+		}
+	}
+	check("labels", "label", propagation.Labels)
+	check("annotations", "annotation", propagation.Annotations)
+	return warnings
+}
+
 // buildExternalRefResource builds an empty resource with metadata from the given externalRef definition.
 func (b *Builder) buildExternalRefResource(
 	externalRef *v1alpha1.ExternalRef) map[string]interface{} {
@@ -294,6 +445,9 @@ func (b *Builder) buildRGResource(
 	if err != nil {
 		return nil, fmt.Errorf("resource %s is not a valid Kubernetes object: %v", rgResource.ID, err)
 	}
+	if err := validateReservedLabels(resourceObject); err != nil {
+		return nil, fmt.Errorf("resource %s: %w", rgResource.ID, err)
+	}
 
 	// 2. Based the GVK, we need to load the OpenAPI schema for the resource.
 	gvk, err := metadata.ExtractGVKFromUnstructured(resourceObject)
@@ -355,6 +509,13 @@ func (b *Builder) buildRGResource(
 		return nil, fmt.Errorf("failed to parse includeWhen expressions: %v", err)
 	}
 
+	// 8. Validate the ReadyWhen JSONPath checks
+	for _, check := range rgResource.ReadyWhenJSONPath {
+		if err := jsonpath.New(rgResource.ID).Parse(check.Path); err != nil {
+			return nil, fmt.Errorf("failed to parse readyWhenJSONPath %s for resource %s: %v", check.Path, rgResource.ID, err)
+		}
+	}
+
 	_, isNamespaced := namespacedResources[gvk.GroupKind()]
 
 	// Note that at this point we don't inject the dependencies into the resource.
@@ -366,10 +527,14 @@ func (b *Builder) buildRGResource(
 		originalObject:         &unstructured.Unstructured{Object: resourceObject},
 		variables:              resourceVariables,
 		readyWhenExpressions:   readyWhen,
+		readyWhenJSONPaths:     slices.Clone(rgResource.ReadyWhenJSONPath),
 		includeWhenExpressions: includeWhen,
 		namespaced:             isNamespaced,
 		order:                  order,
 		isExternalRef:          rgResource.ExternalRef != nil,
+		deleteWithInstance:     rgResource.ExternalRef != nil && rgResource.ExternalRef.DeleteWithInstance,
+		reconcileOnce:          rgResource.Reconcile == v1alpha1.ReconcilePolicyOnce,
+		deletionPropagation:    rgResource.DeletionPropagation,
 	}, nil
 }
 
@@ -457,6 +622,7 @@ func (b *Builder) buildInstanceResource(
 	group, apiVersion, kind string,
 	rgDefinition *v1alpha1.Schema,
 	resources map[string]*Resource,
+	schemaOpts ...simpleschema.Option,
 ) (*Resource, error) {
 	// The instance resource is the resource users will create in their cluster,
 	// to request the creation of the resources defined in the resource graph definition.
@@ -470,7 +636,7 @@ func (b *Builder) buildInstanceResource(
 	gvk := metadata.GetResourceGraphDefinitionInstanceGVK(group, apiVersion, kind)
 
 	// The instance resource has a schema defined using the "SimpleSchema" format.
-	instanceSpecSchema, err := buildInstanceSpecSchema(rgDefinition)
+	instanceSpecSchema, err := buildInstanceSpecSchema(rgDefinition, schemaOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build OpenAPI schema for instance: %w", err)
 	}
@@ -482,7 +648,8 @@ func (b *Builder) buildInstanceResource(
 
 	// Synthesize the CRD for the instance resource.
 	overrideStatusFields := true
-	instanceCRD := crd.SynthesizeCRD(group, apiVersion, kind, *instanceSpecSchema, *instanceStatusSchema, overrideStatusFields, rgDefinition.AdditionalPrinterColumns)
+	scope := extv1.ResourceScope(rgDefinition.Scope)
+	instanceCRD := crd.SynthesizeCRD(group, apiVersion, kind, scope, *instanceSpecSchema, *instanceStatusSchema, overrideStatusFields, rgDefinition.AdditionalPrinterColumns)
 
 	// Emulate the CRD
 	instanceSchemaExt := instanceCRD.Spec.Versions[0].Schema.OpenAPIV3Schema
@@ -501,13 +668,20 @@ func (b *Builder) buildInstanceResource(
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
 
+	computedDefaults, err := buildComputedDefaults(instanceSpecSchema, emulatedInstance, rgDefinition.ComputedDefaults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build computed defaults: %w", err)
+	}
+
 	// The instance resource has a set of variables that need to be resolved.
 	instance := &Resource{
-		id:             "instance",
-		gvr:            metadata.GVKtoGVR(gvk),
-		schema:         instanceSchema,
-		crd:            instanceCRD,
-		emulatedObject: emulatedInstance,
+		id:               "instance",
+		gvr:              metadata.GVKtoGVR(gvk),
+		schema:           instanceSchema,
+		crd:              instanceCRD,
+		emulatedObject:   emulatedInstance,
+		namespaced:       instanceCRD.Spec.Scope == extv1.NamespaceScoped,
+		computedDefaults: computedDefaults,
 	}
 
 	instanceStatusVariables := []*variable.ResourceField{}
@@ -539,7 +713,7 @@ func (b *Builder) buildInstanceResource(
 // buildInstanceSpecSchema builds the instance spec schema that will be
 // used to generate the CRD for the instance resource. The instance spec
 // schema is expected to be defined using the "SimpleSchema" format.
-func buildInstanceSpecSchema(rgSchema *v1alpha1.Schema) (*extv1.JSONSchemaProps, error) {
+func buildInstanceSpecSchema(rgSchema *v1alpha1.Schema, opts ...simpleschema.Option) (*extv1.JSONSchemaProps, error) {
 	// We need to unmarshal the instance schema to a map[string]interface{} to
 	// make it easier to work with.
 	instanceSpec := map[string]interface{}{}
@@ -557,7 +731,7 @@ func buildInstanceSpecSchema(rgSchema *v1alpha1.Schema) (*extv1.JSONSchemaProps,
 	}
 
 	// The instance resource has a schema defined using the "SimpleSchema" format.
-	instanceSchema, err := simpleschema.ToOpenAPISpec(instanceSpec, customTypes)
+	instanceSchema, err := simpleschema.ToOpenAPISpec(instanceSpec, customTypes, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build OpenAPI schema for instance: %v", err)
 	}
@@ -571,9 +745,51 @@ func buildInstanceSpecSchema(rgSchema *v1alpha1.Schema) (*extv1.JSONSchemaProps,
 		}
 	}
 
+	// Add the rules enforcing the mutually exclusive fields.
+	for _, group := range rgSchema.MutuallyExclusive {
+		rule, err := mutuallyExclusiveRule(instanceSchema, group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mutually exclusive fields %v: %w", group, err)
+		}
+		instanceSchema.XValidations = append(instanceSchema.XValidations, rule)
+	}
+
 	return instanceSchema, nil
 }
 
+// mutuallyExclusiveRule returns the validation rule rejecting the instances
+// setting more than one of the given spec fields. The fields must be top level
+// fields of the spec without a default, a defaulted field would always be set.
+// Field names are escaped the way the API server expects them in CEL rules,
+// e.g. "my-field" is accessed as self.my__dash__field.
+func mutuallyExclusiveRule(specSchema *extv1.JSONSchemaProps, fields []string) (extv1.ValidationRule, error) {
+	if len(fields) < 2 {
+		return extv1.ValidationRule{}, fmt.Errorf("at least two fields are needed")
+	}
+	checks := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldSchema, ok := specSchema.Properties[field]
+		if !ok {
+			return extv1.ValidationRule{}, fmt.Errorf("field %s is not a spec field", field)
+		}
+		if fieldSchema.Default != nil {
+			return extv1.ValidationRule{}, fmt.Errorf("field %s has a default value", field)
+		}
+		if slices.Contains(specSchema.Required, field) {
+			return extv1.ValidationRule{}, fmt.Errorf("field %s is required", field)
+		}
+		escaped, ok := apiservercel.Escape(field)
+		if !ok {
+			return extv1.ValidationRule{}, fmt.Errorf("field %s can't be referenced in a CEL rule", field)
+		}
+		checks = append(checks, fmt.Sprintf("has(self.%s)", escaped))
+	}
+	return extv1.ValidationRule{
+		Rule:    fmt.Sprintf("[%s].filter(x, x).size() <= 1", strings.Join(checks, ", ")),
+		Message: fmt.Sprintf("at most one of %s can be set", strings.Join(fields, ", ")),
+	}, nil
+}
+
 // buildStatusSchema builds the status schema for the instance resource. The
 // status schema is inferred from the CEL expressions in the status field.
 func buildStatusSchema(
@@ -620,10 +836,13 @@ func buildStatusSchema(
 				return nil, nil, fmt.Errorf("failed to validate expression context: %w", err)
 			}
 
-			// resources is the context here.
+			// resources is the context here. Dry-running against the emulated
+			// resources also type-checks the expression: a reference to a field
+			// that doesn't exist in the resource schema (e.g a typo in a status
+			// field) fails here, instead of silently never being populated.
 			value, err := dryRunExpression(env, expr, resources)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to dry-run expression: %w", err)
+				return nil, nil, fmt.Errorf("failed to dry-run expression %s for status field %s: %w", expr, found.Path, err)
 			}
 
 			evals = append(evals, value)
@@ -719,6 +938,87 @@ func extractDependencies(env *cel.Env, expression string, resourceNames []string
 	return dependencies, isStatic, nil
 }
 
+// secretsGVR is the GVR of the core Secrets.
+var secretsGVR = k8sschema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// validateSensitiveReferences checks that the instance spec fields marked as
+// sensitive are only rendered into Secrets. Other resources, and the instance
+// status, are not meant to hold secret values and would leak them, e.g in logs
+// and events, and neither are the spec fields computed from them. Referencing a
+// parent of a sensitive field is rejected as well.
+func validateSensitiveReferences(resources map[string]*Resource, instance *Resource, sensitiveFields []string) error {
+	if len(sensitiveFields) == 0 {
+		return nil
+	}
+	// The fields are collected from maps, sort them for the errors to be
+	// deterministic.
+	sensitiveFields = slices.Sorted(slices.Values(sensitiveFields))
+
+	resourceIDs := append(maps.Keys(resources), "schema")
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceIDs))
+	if err != nil {
+		return fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	inspector := ast.NewInspectorWithEnv(env, resourceIDs)
+
+	checkExpression := func(owner, expression string) error {
+		inspection, err := inspector.Inspect(expression)
+		if err != nil {
+			return fmt.Errorf("failed to inspect expression: %w", err)
+		}
+		for _, dependency := range inspection.ResourceDependencies {
+			if dependency.ID != "schema" {
+				continue
+			}
+			for _, field := range sensitiveFields {
+				sensitivePath := "schema.spec." + field
+				if dependency.Path == sensitivePath ||
+					strings.HasPrefix(dependency.Path, sensitivePath+".") ||
+					strings.HasPrefix(sensitivePath, dependency.Path+".") {
+					return fmt.Errorf("%s references sensitive field spec.%s, sensitive fields can only be used in Secrets",
+						owner, field)
+				}
+			}
+		}
+		return nil
+	}
+	checkVariables := func(owner string, variables []*variable.ResourceField) error {
+		for _, v := range variables {
+			for _, expression := range v.Expressions {
+				if err := checkExpression(owner, expression); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	ids := maps.Keys(resources)
+	slices.Sort(ids)
+	for _, id := range ids {
+		if resources[id].gvr == secretsGVR {
+			continue
+		}
+		if err := checkVariables("resource "+id, resources[id].variables); err != nil {
+			return err
+		}
+	}
+
+	// A computed default would copy the sensitive value into a field that can
+	// be rendered anywhere, unless that field is sensitive as well.
+	fields := maps.Keys(instance.computedDefaults)
+	slices.Sort(fields)
+	for _, field := range fields {
+		if slices.Contains(sensitiveFields, field) {
+			continue
+		}
+		if err := checkExpression("computed default for field "+field, instance.computedDefaults[field]); err != nil {
+			return err
+		}
+	}
+	return checkVariables("instance status", instance.variables)
+}
+
 // validateResourceCELExpressions tries to validate the CEL expressions in the
 // resources against the resources defined in the resource graph definition.
 //
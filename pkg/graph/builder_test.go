@@ -17,10 +17,13 @@ package graph
 import (
 	"testing"
 
+	"github.com/google/cel-go/cel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 
+	"github.com/kro-run/kro/api/v1alpha1"
 	"github.com/kro-run/kro/pkg/graph/emulator"
 	"github.com/kro-run/kro/pkg/graph/variable"
 	"github.com/kro-run/kro/pkg/testutil/generator"
@@ -336,6 +339,54 @@ func TestGraphBuilder_Validation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "undeclared reference to 'nonexistent'",
 		},
+		{
+			name: "invalid instance status field reference to unknown resource field",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					map[string]interface{}{
+						"vpcID": "${vpc.status.vpcIDD}", // typo in status field
+					},
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "for status field vpcID: failed to evaluate expression: no such key: vpcIDD",
+		},
+		{
+			name: "invalid nested instance status field reference in string template",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					map[string]interface{}{
+						"network": map[string]interface{}{
+							"summary": "vpc-${vpc.status.nonexistent.field}",
+						},
+					},
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "for status field network.summary",
+		},
 		{
 			name: "invalid field type in resource spec",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
@@ -447,6 +498,121 @@ func TestGraphBuilder_Validation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "optional value omitting a field",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"autoscale": "boolean",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+						"annotations": map[string]interface{}{
+							"autoscaling": `${schema.spec.autoscale ? optional.of("true") : optional.none()}`,
+						},
+					},
+				}, nil, nil),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ready gate referencing a resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithReadyGate("vpc"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "resource referencing an array of objects of the schema",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"subnets": []interface{}{
+							map[string]interface{}{
+								"name": "string | required=true",
+								"cidr": "string | default=\"10.0.0.0/24\"",
+							},
+						},
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.subnets[0].name}",
+					},
+				}, nil, nil),
+			},
+			wantErr: false,
+		},
+		{
+			name: "template setting a label reserved by kro",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+						"labels": map[string]interface{}{
+							"kro.run/instance-id": "${schema.spec.name}",
+						},
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "resource vpc: labels kro.run/instance-id are reserved by kro",
+		},
+		{
+			name: "ready gate referencing an unknown resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithReadyGate("vpc", "subnet"),
+			},
+			wantErr: true,
+			errMsg:  `resource "subnet" is not defined`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1026,6 +1192,48 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 				assert.Equal(t, "message", spec.XValidations[0].Message)
 			},
 		},
+		{
+			name: "check mutually exclusive fields",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name":     "string",
+						"image":    "string",
+						"imageRef": "string",
+					},
+					nil,
+				),
+				generator.WithValidation("rule", "message"),
+				generator.WithMutuallyExclusive("image", "imageRef"),
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				require.Len(t, g.Instance.crd.Spec.Versions, 1)
+				schema := g.Instance.crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+				require.Contains(t, schema.Properties, "spec")
+				spec := schema.Properties["spec"]
+
+				require.Len(t, spec.XValidations, 2)
+				assert.Equal(t, "rule", spec.XValidations[0].Rule)
+				assert.Equal(t, "[has(self.image), has(self.imageRef)].filter(x, x).size() <= 1", spec.XValidations[1].Rule)
+				assert.Equal(t, "at most one of image, imageRef can be set", spec.XValidations[1].Message)
+			},
+		},
+		{
+			name: "mutually exclusive unknown field",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"image": "string",
+					},
+					nil,
+				),
+				generator.WithMutuallyExclusive("image", "imageRef"),
+			},
+			wantErr: true,
+			errMsg:  "field imageRef is not a spec field",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1450,3 +1658,737 @@ func Test_ValidateOpenAPISchema(t *testing.T) {
 		})
 	}
 }
+
+func TestGraphBuilder_DeprecatedField(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name":      "string",
+				"cidrBlock": `string | deprecated="use cidrBlocks instead"`,
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+		}, nil, nil),
+	)
+
+	g, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+
+	crd := g.Instance.GetCRD()
+	require.Len(t, crd.Spec.Versions, 1)
+	spec := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	assert.Equal(t, "Deprecated: use cidrBlocks instead", spec.Properties["cidrBlock"].Description)
+	assert.Empty(t, spec.Properties["name"].Description)
+}
+
+func TestGraphBuilder_SensitiveField(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	secret := generator.WithResource("secret", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name": "${schema.spec.name}",
+		},
+		"stringData": map[string]interface{}{
+			"password": "${schema.spec.credentials.password}",
+		},
+	}, nil, nil)
+
+	tests := []struct {
+		name    string
+		options []generator.ResourceGraphDefinitionOption
+		wantErr string
+	}{
+		{
+			name:    "rendered into a secret",
+			options: []generator.ResourceGraphDefinitionOption{secret},
+		},
+		{
+			name: "rendered into another resource",
+			options: []generator.ResourceGraphDefinitionOption{
+				secret,
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${secret.metadata.name}",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "app",
+								"image": "${schema.spec.credentials.password}",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			wantErr: "resource pod references sensitive field spec.credentials.password",
+		},
+		{
+			name: "parent rendered into another resource",
+			options: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name":        "${schema.spec.name}",
+						"annotations": "${schema.spec.credentials}",
+					},
+				}, nil, nil),
+			},
+			wantErr: "resource pod references sensitive field spec.credentials.password",
+		},
+		{
+			name: "custom type field",
+			options: []generator.ResourceGraphDefinitionOption{
+				secret,
+				generator.WithTypes(map[string]interface{}{
+					"Token": map[string]interface{}{
+						"value": "string | sensitive=true",
+					},
+				}),
+			},
+			wantErr: "sensitive marker is not supported on the fields of custom types",
+		},
+		{
+			name: "custom type rendered into another resource",
+			options: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.token.value}",
+					},
+				}, nil, nil),
+			},
+			wantErr: "resource pod references sensitive field spec.token",
+		},
+		{
+			name: "computed into another field",
+			options: []generator.ResourceGraphDefinitionOption{
+				secret,
+				generator.WithComputedDefault("label", "${schema.spec.credentials.password}"),
+			},
+			wantErr: "computed default for field label references sensitive field spec.credentials.password",
+		},
+		{
+			name: "computed into a sensitive field",
+			options: []generator.ResourceGraphDefinitionOption{
+				secret,
+				generator.WithComputedDefault("credentials.token", "${schema.spec.credentials.password}"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name":  "string",
+						"label": "string",
+						"token": "Token | sensitive=true",
+						"credentials": map[string]interface{}{
+							"password": "string | sensitive=true",
+							"token":    "string | sensitive=true",
+						},
+					},
+					nil,
+				),
+				generator.WithTypes(map[string]interface{}{
+					"Token": map[string]interface{}{
+						"value": "string",
+					},
+				}),
+			}, tt.options...)
+			rgd := generator.NewResourceGraphDefinition("test-group", options...)
+
+			_, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGraphBuilder_InstanceMetadata(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name": "string",
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name + '-' + schema.metadata.uid}",
+			},
+			"spec": map[string]interface{}{
+				"enableDNSSupport": "${timestamp(schema.metadata.creationTimestamp) > timestamp('2025-01-01T00:00:00Z')}",
+			},
+		}, nil, nil),
+	)
+
+	_, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+}
+
+func TestGraphBuilder_ReadyExpression(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name             string
+		readyExpression  string
+		wantExpression   string
+		wantDependencies []string
+		wantErr          string
+	}{
+		{
+			name:             "expression combining two resources",
+			readyExpression:  "${vpc.status.state == 'available' && subnet.status.state == 'available'}",
+			wantExpression:   "vpc.status.state == 'available' && subnet.status.state == 'available'",
+			wantDependencies: []string{"vpc", "subnet"},
+		},
+		{
+			name:             "expression allowing an ignored resource",
+			readyExpression:  "${vpc.status.state == 'available' && (subnet == null || subnet.status.state == 'available')}",
+			wantExpression:   "vpc.status.state == 'available' && (subnet == null || subnet.status.state == 'available')",
+			wantDependencies: []string{"vpc", "subnet"},
+		},
+		{
+			name:            "not a standalone expression",
+			readyExpression: "${vpc.status.state}-${subnet.status.state}",
+			wantErr:         "only standalone expressions are allowed",
+		},
+		{
+			name:            "expression not referencing a resource",
+			readyExpression: "${true}",
+			wantErr:         "ready expression true must refer to a resource",
+		},
+		{
+			name:            "expression referencing the instance",
+			readyExpression: "${schema.spec.name == 'test'}",
+			wantErr:         "found unknown resources",
+		},
+		{
+			name:            "expression not returning a bool",
+			readyExpression: "${vpc.status.state}",
+			wantErr:         "output of ready expression vpc.status.state can only be of type bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-group",
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+					},
+				}, nil, nil),
+				generator.WithReadyExpression(tt.readyExpression),
+			)
+
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpression, g.ReadyExpression)
+			assert.ElementsMatch(t, tt.wantDependencies, g.readyExpressionDependencies)
+		})
+	}
+}
+
+func TestGraphBuilder_QuantityExpressions(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name":     "string",
+				"memory":   "string | default=\"512Mi\"",
+				"replicas": "integer | default=1",
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+				"annotations": map[string]interface{}{
+					"memory":      "${scaleQuantity(schema.spec.memory, schema.spec.replicas)}",
+					"memoryLimit": "${addQuantity(schema.spec.memory, '256Mi')}",
+				},
+			},
+		}, nil, nil),
+	)
+	g, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name":     "test",
+			"memory":   "1Gi",
+			"replicas": int64(3),
+		},
+	}}
+	rt, err := g.NewGraphRuntime(instance)
+	require.NoError(t, err)
+
+	resource, _ := rt.GetResource("vpc")
+	require.NotNil(t, resource)
+	assert.Equal(t, map[string]string{
+		"memory":      "3Gi",
+		"memoryLimit": "1280Mi",
+	}, resource.GetAnnotations())
+}
+
+func TestGraphBuilder_ListMapping(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name":  "string",
+				"ports": "[]Port",
+			},
+			nil,
+		),
+		generator.WithResource("pod", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name":  "app",
+						"image": "nginx",
+						"ports": "${schema.spec.ports.map(p, {'name': p.name, 'containerPort': p.port, 'protocol': 'TCP'})}",
+					},
+				},
+			},
+		}, nil, nil),
+	)
+	rgd.Spec.Schema.Types.Raw = []byte(`{"Port": {"name": "string", "port": "integer"}}`)
+
+	g, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+
+	instance := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name": "test",
+			"ports": []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(8080)},
+				map[string]interface{}{"name": "metrics", "port": int64(9090)},
+			},
+		},
+	}}
+	rt, err := g.NewGraphRuntime(instance)
+	require.NoError(t, err)
+
+	resource, _ := rt.GetResource("pod")
+	require.NotNil(t, resource)
+	containers, _, err := unstructured.NestedSlice(resource.Object, "spec", "containers")
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "http", "containerPort": int64(8080), "protocol": "TCP"},
+		map[string]interface{}{"name": "metrics", "containerPort": int64(9090), "protocol": "TCP"},
+	}, containers[0].(map[string]interface{})["ports"])
+}
+
+func TestGraphBuilder_ComputedDefaults(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	schema := generator.WithSchema(
+		"Test", "v1alpha1",
+		map[string]interface{}{
+			"firstName": "string",
+			"lastName":  "string",
+			"fullName":  "string",
+			"nickname":  "string",
+			"region":    "string | default=us-west-2",
+			"owner":     "string | required=true",
+			"replicas":  "integer",
+		},
+		nil,
+	)
+	vpc := generator.WithResource("vpc", map[string]interface{}{
+		"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+		"kind":       "VPC",
+		"metadata": map[string]interface{}{
+			"name": "${schema.spec.fullName}",
+		},
+	}, nil, nil)
+
+	t.Run("unset field is derived from other spec fields", func(t *testing.T) {
+		rgd := generator.NewResourceGraphDefinition("test-group",
+			schema, vpc,
+			generator.WithComputedDefault("fullName", "${schema.spec.firstName + '-' + schema.spec.lastName}"),
+		)
+		g, err := builder.NewResourceGraphDefinition(rgd)
+		require.NoError(t, err)
+
+		instance := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"firstName": "jane",
+				"lastName":  "doe",
+				"owner":     "team",
+			},
+		}}
+		rt, err := g.NewGraphRuntime(instance)
+		require.NoError(t, err)
+
+		resource, _ := rt.GetResource("vpc")
+		require.NotNil(t, resource)
+		assert.Equal(t, "jane-doe", resource.GetName())
+
+		// The computed value is never written to the instance.
+		_, found, _ := unstructured.NestedString(rt.GetInstance().Object, "spec", "fullName")
+		assert.False(t, found)
+	})
+
+	t.Run("set field is kept", func(t *testing.T) {
+		rgd := generator.NewResourceGraphDefinition("test-group",
+			schema, vpc,
+			generator.WithComputedDefault("fullName", "${schema.spec.firstName + '-' + schema.spec.lastName}"),
+		)
+		g, err := builder.NewResourceGraphDefinition(rgd)
+		require.NoError(t, err)
+
+		instance := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"firstName": "jane",
+				"lastName":  "doe",
+				"fullName":  "jdoe",
+				"owner":     "team",
+			},
+		}}
+		rt, err := g.NewGraphRuntime(instance)
+		require.NoError(t, err)
+
+		resource, _ := rt.GetResource("vpc")
+		require.NotNil(t, resource)
+		assert.Equal(t, "jdoe", resource.GetName())
+	})
+
+	errorCases := []struct {
+		name       string
+		field      string
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "unknown field",
+			field:      "middleName",
+			expression: "${schema.spec.firstName}",
+			wantErr:    `computed default refers to unknown spec field "middleName"`,
+		},
+		{
+			name:       "field with a default",
+			field:      "region",
+			expression: "${schema.spec.firstName}",
+			wantErr:    `computed default field "region" already has a default value`,
+		},
+		{
+			name:       "required field",
+			field:      "owner",
+			expression: "${schema.spec.firstName}",
+			wantErr:    `computed default field "owner" can't be required`,
+		},
+		{
+			name:       "not a standalone expression",
+			field:      "fullName",
+			expression: "${schema.spec.firstName}-${schema.spec.lastName}",
+			wantErr:    "only standalone expressions are allowed",
+		},
+		{
+			name:       "refers to a resource",
+			field:      "fullName",
+			expression: "${vpc.metadata.name}",
+			wantErr:    `computed default for field "fullName" can only refer to schema.spec fields`,
+		},
+		{
+			name:       "refers to another computed field",
+			field:      "fullName",
+			expression: "${schema.spec.nickname}",
+			wantErr:    `computed default for field "fullName" refers to computed field "nickname"`,
+		},
+		{
+			name:       "string for an integer field",
+			field:      "replicas",
+			expression: "${schema.spec.firstName}",
+			wantErr:    `computed default for field "replicas" evaluates to a string, but the field is of type integer`,
+		},
+		{
+			name:       "integer for a string field",
+			field:      "fullName",
+			expression: "${size(schema.spec.firstName)}",
+			wantErr:    `computed default for field "fullName" evaluates to a int, but the field is of type string`,
+		},
+	}
+
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-group",
+				schema, vpc,
+				generator.WithComputedDefault("nickname", "${schema.spec.firstName}"),
+				generator.WithComputedDefault(tt.field, tt.expression),
+			)
+			_, err := builder.NewResourceGraphDefinition(rgd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGraphBuilder_UnknownSchemaMarkers(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name": `string | requried=true`,
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+		}, nil, nil),
+	)
+
+	t.Run("strict", func(t *testing.T) {
+		builder := &Builder{
+			schemaResolver:   fakeResolver,
+			discoveryClient:  fakeDiscovery,
+			resourceEmulator: emulator.NewEmulator(),
+		}
+		_, err := builder.NewResourceGraphDefinition(rgd)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown marker "requried" on field name`)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		builder := &Builder{
+			schemaResolver:   fakeResolver,
+			discoveryClient:  fakeDiscovery,
+			resourceEmulator: emulator.NewEmulator(),
+		}
+		WithLenientSchemaMarkers()(builder)
+		g, err := builder.NewResourceGraphDefinition(rgd)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`ignoring unknown marker "requried" on field name`}, g.Warnings)
+
+		spec := g.Instance.GetCRD().Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+		assert.Empty(t, spec.Required)
+	})
+}
+
+func TestGraphBuilder_PropagationConflicts(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+	newRGD := func(precedence v1alpha1.PropagationPrecedence) *v1alpha1.ResourceGraphDefinition {
+		rgd := generator.NewResourceGraphDefinition("test-group",
+			generator.WithSchema(
+				"Test", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("vpc", map[string]interface{}{
+				"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+				"kind":       "VPC",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+					"labels": map[string]interface{}{
+						"team": "platform",
+						"tier": "${schema.spec.name}",
+					},
+					"annotations": map[string]interface{}{
+						"owner": "ops",
+					},
+				},
+			}, nil, nil),
+		)
+		rgd.Spec.Propagate = &v1alpha1.Propagation{
+			Labels:      []string{"team", "tier", "environment"},
+			Annotations: []string{"owner"},
+			Precedence:  precedence,
+		}
+		return rgd
+	}
+
+	g, err := builder.NewResourceGraphDefinition(newRGD(""))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`resource "vpc" sets the propagated label "team": the instance value takes precedence`,
+		`resource "vpc" sets the propagated label "tier": the instance value takes precedence`,
+		`resource "vpc" sets the propagated annotation "owner": the instance value takes precedence`,
+	}, g.Warnings)
+
+	g, err = builder.NewResourceGraphDefinition(newRGD(v1alpha1.PropagationPrecedenceTemplate))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`resource "vpc" sets the propagated label "team": the template value takes precedence`,
+		`resource "vpc" sets the propagated label "tier": the template value takes precedence`,
+		`resource "vpc" sets the propagated annotation "owner": the template value takes precedence`,
+	}, g.Warnings)
+}
+
+func TestMutuallyExclusiveRule(t *testing.T) {
+	specSchema := &extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"a":         {Type: "string"},
+			"b":         {Type: "string"},
+			"c":         {Type: "integer"},
+			"required":  {Type: "string"},
+			"default":   {Type: "string", Default: &extv1.JSON{Raw: []byte(`"value"`)}},
+			"my-field":  {Type: "string"},
+			"namespace": {Type: "string"},
+			"a__b":      {Type: "string"},
+			"a b":       {Type: "string"},
+		},
+		Required: []string{"required"},
+	}
+
+	t.Run("rule", func(t *testing.T) {
+		rule, err := mutuallyExclusiveRule(specSchema, []string{"a", "b", "c"})
+		require.NoError(t, err)
+
+		env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+		require.NoError(t, err)
+		ast, issues := env.Compile(rule.Rule)
+		require.NoError(t, issues.Err())
+		program, err := env.Program(ast)
+		require.NoError(t, err)
+
+		for _, tt := range []struct {
+			spec map[string]interface{}
+			want bool
+		}{
+			{map[string]interface{}{}, true},
+			{map[string]interface{}{"a": "x"}, true},
+			{map[string]interface{}{"c": 1}, true},
+			{map[string]interface{}{"a": "x", "b": "y"}, false},
+			{map[string]interface{}{"a": "x", "b": "y", "c": 1}, false},
+		} {
+			out, _, err := program.Eval(map[string]interface{}{"self": tt.spec})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.Value(), "spec %v", tt.spec)
+		}
+	})
+
+	t.Run("escaped fields", func(t *testing.T) {
+		rule, err := mutuallyExclusiveRule(specSchema, []string{"my-field", "namespace", "a__b"})
+		require.NoError(t, err)
+		assert.Equal(t,
+			"[has(self.my__dash__field), has(self.__namespace__), has(self.a__underscores__b)].filter(x, x).size() <= 1",
+			rule.Rule)
+	})
+
+	for _, tt := range []struct {
+		name    string
+		fields  []string
+		wantErr string
+	}{
+		{"single field", []string{"a"}, "at least two fields are needed"},
+		{"unknown field", []string{"a", "unknown"}, "field unknown is not a spec field"},
+		{"defaulted field", []string{"a", "default"}, "field default has a default value"},
+		{"required field", []string{"a", "required"}, "field required is required"},
+		{"field not valid in CEL", []string{"a", "a b"}, "field a b can't be referenced in a CEL rule"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mutuallyExclusiveRule(specSchema, tt.fields)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
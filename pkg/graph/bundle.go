@@ -0,0 +1,104 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// RenderBundle resolves the resources of the graph for the given instance and
+// renders them as a multi-document YAML, in the order they would be applied
+// by the instance controller. The metadata the controller adds to the
+// sub-resources (the instance labels merged with the given labeler, the
+// propagated instance labels and annotations, and the resource id annotation)
+// is included, so the output can be reviewed or piped into `kubectl apply`.
+//
+// Rendering happens offline: each rendered object is fed back to the runtime
+// as if it was observed in the cluster. Resources referencing fields that are
+// only known once applied (e.g a status field) can't be rendered, and an
+// error is returned. Resources excluded by their includeWhen expressions and
+// external references are left out of the bundle.
+func RenderBundle(g *Graph, instance *unstructured.Unstructured, labeler metadata.Labeler) ([]byte, error) {
+	rt, err := g.NewGraphRuntime(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime: %w", err)
+	}
+
+	subResourcesLabeler := metadata.Labeler(metadata.NewInstanceLabeler(instance))
+	if labeler != nil {
+		subResourcesLabeler, err = metadata.NewInstanceLabeler(instance).Merge(labeler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create instance sub-resources labeler: %w", err)
+		}
+	}
+	subResourceMetadata, err := metadata.NewSubResourceMetadata(instance, subResourcesLabeler, g.Propagation)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, resourceID := range rt.TopologicalOrder() {
+		if _, err := rt.Synchronize(); err != nil {
+			return nil, fmt.Errorf("failed to resolve resources offline: %w", err)
+		}
+
+		if want, err := rt.ReadyToProcessResource(resourceID); err != nil || !want {
+			rt.IgnoreResource(resourceID)
+			continue
+		}
+
+		resource, state := rt.GetResource(resourceID)
+		if state != runtime.ResourceStateResolved {
+			return nil, fmt.Errorf("resource %s can't be resolved offline: state=%v", resourceID, state)
+		}
+
+		descriptor := rt.ResourceDescriptor(resourceID)
+		if descriptor.IsExternalRef() {
+			// External references are read from the cluster, we can't do
+			// better than their declared metadata.
+			rt.SetResource(resourceID, resource)
+			continue
+		}
+
+		obj := resource.DeepCopy()
+		if descriptor.IsNamespaced() && obj.GetNamespace() == "" {
+			namespace := instance.GetNamespace()
+			if namespace == "" {
+				namespace = metav1.NamespaceDefault
+			}
+			obj.SetNamespace(namespace)
+		}
+		subResourceMetadata.Apply(resourceID, obj)
+
+		out, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource %s: %w", resourceID, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(out)
+
+		rt.SetResource(resourceID, obj)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/graph/emulator"
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+	"github.com/kro-run/kro/pkg/testutil/k8s"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestRenderBundle(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("testrgd",
+		generator.WithSchema(
+			"Network", "v1alpha1",
+			map[string]interface{}{
+				"name":      "string",
+				"cidrBlock": "string | default=\"10.0.0.0/16\"",
+				"subnet":    "boolean | default=true",
+			},
+			nil,
+		),
+		// declared after the subnet, to make sure the apply order is respected
+		generator.WithResource("subnet", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "Subnet",
+			"metadata": map[string]interface{}{
+				"name": "${vpc.metadata.name}-subnet",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlock": "${schema.spec.cidrBlock}",
+			},
+		}, nil, []string{"${schema.spec.subnet}"}),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}-vpc",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlocks": []interface{}{"${schema.spec.cidrBlock}"},
+			},
+		}, nil, nil),
+		generator.WithResource("securitygroup", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "SecurityGroup",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}-sg",
+			},
+			"spec": map[string]interface{}{
+				"vpcID": "${vpc.status.vpcID}",
+			},
+		}, nil, []string{"${schema.spec.name == 'with-sg'}"}),
+	)
+	rgd.UID = "00000000-0000-0000-0000-000000000001"
+	rgd.Spec.Propagate = &v1alpha1.Propagation{
+		Labels:      []string{"team"},
+		Annotations: []string{"cost-center"},
+	}
+
+	g, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+
+	newInstance := func(spec map[string]interface{}) *unstructured.Unstructured {
+		instance := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "Network",
+			"metadata": map[string]interface{}{
+				"name":      "my-network",
+				"namespace": "team-a",
+				"uid":       "00000000-0000-0000-0000-000000000002",
+				"labels": map[string]interface{}{
+					"team": "networking",
+				},
+				"annotations": map[string]interface{}{
+					"cost-center": "1234",
+				},
+			},
+			"spec": spec,
+		}}
+		return instance
+	}
+
+	t.Run("renders resolvable resources in apply order", func(t *testing.T) {
+		bundle, err := RenderBundle(g, newInstance(map[string]interface{}{
+			"name":      "prod",
+			"cidrBlock": "10.1.0.0/16",
+			"subnet":    true,
+		}), metadata.NewResourceGraphDefinitionLabeler(rgd))
+		require.NoError(t, err)
+
+		golden := filepath.Join("testdata", "render_bundle.golden.yaml")
+		if *updateGolden {
+			require.NoError(t, os.WriteFile(golden, bundle, 0o644))
+		}
+		want, err := os.ReadFile(golden)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(bundle))
+	})
+
+	t.Run("fails on values only known once applied", func(t *testing.T) {
+		_, err := RenderBundle(g, newInstance(map[string]interface{}{
+			"name":      "with-sg",
+			"cidrBlock": "10.1.0.0/16",
+			"subnet":    true,
+		}), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vpc.status.vpcID")
+	})
+}
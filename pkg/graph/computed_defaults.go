@@ -0,0 +1,221 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/cel/ast"
+	"github.com/kro-run/kro/pkg/graph/parser"
+)
+
+// buildComputedDefaults validates the computed defaults declared in the
+// resource graph definition schema and returns them with the '${}' removed.
+//
+// A computed default must target an optional spec field without a static
+// default, and its expression can only refer to the other spec fields of the
+// instance. It must evaluate to a value of the type of the field. Computed defaults can't refer to each other, so they can be
+// evaluated in any order.
+func buildComputedDefaults(
+	specSchema *extv1.JSONSchemaProps,
+	emulatedInstance *unstructured.Unstructured,
+	computedDefaults map[string]string,
+) (map[string]string, error) {
+	if len(computedDefaults) == 0 {
+		return nil, nil
+	}
+
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	inspector := ast.NewInspectorWithEnv(env, []string{"schema"})
+
+	emulatedCopy := emulatedInstance.DeepCopy()
+	delete(emulatedCopy.Object, "apiVersion")
+	delete(emulatedCopy.Object, "kind")
+	delete(emulatedCopy.Object, "status")
+	context := map[string]*Resource{
+		"schema": {emulatedObject: emulatedCopy},
+	}
+
+	fields := make([]string, 0, len(computedDefaults))
+	for field := range computedDefaults {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	expressions := make(map[string]string, len(computedDefaults))
+	for _, field := range fields {
+		fieldSchema, err := validateComputedDefaultField(specSchema, field)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := parser.ParseConditionExpressions([]string{computedDefaults[field]})
+		if err != nil {
+			return nil, fmt.Errorf("invalid computed default for field %q: %w", field, err)
+		}
+		expression := parsed[0]
+
+		inspection, err := inspector.Inspect(expression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect computed default for field %q: %w", field, err)
+		}
+		if len(inspection.UnknownResources) > 0 {
+			return nil, fmt.Errorf("computed default for field %q can only refer to schema.spec fields", field)
+		}
+		for _, dependency := range inspection.ResourceDependencies {
+			if dependency.Path == "schema.spec" || !strings.HasPrefix(dependency.Path, "schema.spec.") {
+				return nil, fmt.Errorf("computed default for field %q can only refer to schema.spec fields", field)
+			}
+			for _, other := range fields {
+				otherPath := "schema.spec." + other
+				if dependency.Path == otherPath ||
+					strings.HasPrefix(dependency.Path, otherPath+".") ||
+					strings.HasPrefix(otherPath, dependency.Path+".") {
+					return nil, fmt.Errorf("computed default for field %q refers to computed field %q", field, other)
+				}
+			}
+		}
+
+		output, err := dryRunExpression(env, expression, context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dry-run computed default for field %q: %w", field, err)
+		}
+		if !matchesSchemaType(fieldSchema, output) {
+			return nil, fmt.Errorf("computed default for field %q evaluates to a %s, but the field is of type %s",
+				field, output.Type().TypeName(), schemaTypeName(fieldSchema))
+		}
+		expressions[field] = expression
+	}
+	return expressions, nil
+}
+
+// validateComputedDefaultField makes sure the given dotted path points to an
+// optional spec field without a static default, and returns its schema.
+func validateComputedDefaultField(specSchema *extv1.JSONSchemaProps, field string) (*extv1.JSONSchemaProps, error) {
+	current := specSchema
+	for _, part := range strings.Split(field, ".") {
+		property, ok := current.Properties[part]
+		if !ok {
+			return nil, fmt.Errorf("computed default refers to unknown spec field %q", field)
+		}
+		if slices.Contains(current.Required, part) {
+			return nil, fmt.Errorf("computed default field %q can't be required", field)
+		}
+		current = &property
+	}
+	if current.Default != nil {
+		return nil, fmt.Errorf("computed default field %q already has a default value", field)
+	}
+	return current, nil
+}
+
+// matchesSchemaType returns true if the CEL value can be set on a field of
+// the given schema. Integers are accepted for number fields, as JSON doesn't
+// tell them apart.
+func matchesSchemaType(fieldSchema *extv1.JSONSchemaProps, value ref.Val) bool {
+	if fieldSchema.XIntOrString {
+		return value.Type() == types.IntType || value.Type() == types.StringType
+	}
+	switch fieldSchema.Type {
+	case "string":
+		return value.Type() == types.StringType
+	case "integer":
+		return value.Type() == types.IntType || value.Type() == types.UintType
+	case "number":
+		return value.Type() == types.DoubleType || value.Type() == types.IntType || value.Type() == types.UintType
+	case "boolean":
+		return value.Type() == types.BoolType
+	case "array":
+		return value.Type() == types.ListType
+	case "object":
+		return value.Type() == types.MapType
+	default:
+		// Untyped fields, e.g. preserving unknown fields, accept any value.
+		return true
+	}
+}
+
+// schemaTypeName returns the type of the field schema, for error messages.
+func schemaTypeName(fieldSchema *extv1.JSONSchemaProps) string {
+	if fieldSchema.XIntOrString {
+		return "int-or-string"
+	}
+	return fieldSchema.Type
+}
+
+// applyComputedDefaults evaluates the computed defaults against the instance
+// and sets every unset field to the value of its expression. The instance is
+// modified in place.
+func applyComputedDefaults(instance *unstructured.Unstructured, computedDefaults map[string]string) error {
+	if len(computedDefaults) == 0 {
+		return nil
+	}
+
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		return fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	context := map[string]interface{}{
+		"schema": instance.DeepCopy().Object,
+	}
+
+	for field, expression := range computedDefaults {
+		path := append([]string{"spec"}, strings.Split(field, ".")...)
+		if _, found, _ := unstructured.NestedFieldNoCopy(instance.Object, path...); found {
+			continue
+		}
+
+		compiled, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			return fmt.Errorf("failed compiling computed default for field %q: %w", field, issues.Err())
+		}
+		program, err := env.Program(compiled)
+		if err != nil {
+			return fmt.Errorf("failed programming computed default for field %q: %w", field, err)
+		}
+		val, _, err := program.Eval(context)
+		if err != nil {
+			return fmt.Errorf("failed evaluating computed default for field %q: %w", field, err)
+		}
+		value, err := krocel.GoNativeType(val)
+		if err != nil {
+			return fmt.Errorf("failed converting computed default for field %q: %w", field, err)
+		}
+		// The value comes straight from CEL, so we set it without going through
+		// unstructured.SetNestedField, which only accepts JSON compatible types.
+		parent := instance.Object
+		for _, part := range path[:len(path)-1] {
+			child, ok := parent[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[part] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = value
+	}
+	return nil
+}
@@ -30,11 +30,13 @@ func TestSynthesizeCRD(t *testing.T) {
 		group                string
 		apiVersion           string
 		kind                 string
+		scope                extv1.ResourceScope
 		spec                 extv1.JSONSchemaProps
 		status               extv1.JSONSchemaProps
 		statusFieldsOverride bool
 		expectedName         string
 		expectedGroup        string
+		expectedScope        extv1.ResourceScope
 	}{
 		{
 			name:                 "standard group and kind",
@@ -46,6 +48,7 @@ func TestSynthesizeCRD(t *testing.T) {
 			statusFieldsOverride: true,
 			expectedName:         "widgets.kro.com",
 			expectedGroup:        "kro.com",
+			expectedScope:        extv1.NamespaceScoped,
 		},
 		{
 			name:                 "empty group uses default domain",
@@ -57,6 +60,7 @@ func TestSynthesizeCRD(t *testing.T) {
 			statusFieldsOverride: false,
 			expectedName:         "services." + v1alpha1.KRODomainName,
 			expectedGroup:        v1alpha1.KRODomainName,
+			expectedScope:        extv1.NamespaceScoped,
 		},
 		{
 			name:                 "mixes case kind",
@@ -68,17 +72,32 @@ func TestSynthesizeCRD(t *testing.T) {
 			statusFieldsOverride: true,
 			expectedName:         "databases.kro.com",
 			expectedGroup:        "kro.com",
+			expectedScope:        extv1.NamespaceScoped,
+		},
+		{
+			name:                 "cluster scope",
+			group:                "kro.com",
+			apiVersion:           "v1",
+			kind:                 "Policy",
+			scope:                extv1.ClusterScoped,
+			spec:                 extv1.JSONSchemaProps{Type: "object"},
+			status:               extv1.JSONSchemaProps{Type: "object"},
+			statusFieldsOverride: true,
+			expectedName:         "policies.kro.com",
+			expectedGroup:        "kro.com",
+			expectedScope:        extv1.ClusterScoped,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			crd := SynthesizeCRD(tt.group, tt.apiVersion, tt.kind, tt.spec, tt.status, tt.statusFieldsOverride, nil)
+			crd := SynthesizeCRD(tt.group, tt.apiVersion, tt.kind, tt.scope, tt.spec, tt.status, tt.statusFieldsOverride, nil)
 
 			assert.Equal(t, tt.expectedName, crd.Name)
 			assert.Equal(t, tt.expectedGroup, crd.Spec.Group)
 			assert.Equal(t, tt.kind, crd.Spec.Names.Kind)
 			assert.Equal(t, tt.kind+"List", crd.Spec.Names.ListKind)
+			assert.Equal(t, tt.expectedScope, crd.Spec.Scope)
 
 			require.Len(t, crd.Spec.Versions, 1)
 			version := crd.Spec.Versions[0]
@@ -194,7 +213,7 @@ func TestNewCRD(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			schema := &extv1.JSONSchemaProps{Type: "object"}
-			crd := newCRD(tt.group, tt.apiVersion, tt.kind, schema, tt.printerColumns)
+			crd := newCRD(tt.group, tt.apiVersion, tt.kind, extv1.NamespaceScoped, schema, tt.printerColumns)
 
 			assert.Equal(t, tt.expectedName, crd.Name)
 			assert.Equal(t, tt.group, crd.Spec.Group)
@@ -277,6 +296,8 @@ func TestNewCRDSchema(t *testing.T) {
 			if tt.expectedStateField {
 				assert.Contains(t, statusProps.Properties, "state")
 				assert.Equal(t, defaultConditionsType, statusProps.Properties["conditions"])
+				assert.Equal(t, defaultResourceSummaryType, statusProps.Properties["resourceSummary"])
+				assert.Equal(t, defaultObservedRGDGenerationType, statusProps.Properties["observedRGDGeneration"])
 			}
 
 			if tt.status.Properties != nil {
@@ -16,17 +16,23 @@ package crd
 
 import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
 )
 
 var (
 	defaultStateType = extv1.JSONSchemaProps{
 		Type: "string",
 	}
+	// The conditions are a list map keyed by type, so that conditions set by
+	// other controllers are merged, not replaced, when kro applies the status.
 	defaultConditionsType = extv1.JSONSchemaProps{
-		Type: "array",
+		Type:         "array",
+		XListType:    ptr.To("map"),
+		XListMapKeys: []string{"type"},
 		Items: &extv1.JSONSchemaPropsOrArray{
 			Schema: &extv1.JSONSchemaProps{
-				Type: "object",
+				Type:     "object",
+				Required: []string{"type"},
 				Properties: map[string]extv1.JSONSchemaProps{
 					"type": {
 						Type: "string", // Boolean maybe?
@@ -50,6 +56,27 @@ var (
 			},
 		},
 	}
+	// The generation of the ResourceGraphDefinition the instance was last
+	// reconciled against.
+	defaultObservedRGDGenerationType = extv1.JSONSchemaProps{
+		Type: "integer",
+	}
+	// The resource summary counts the resources of the instance by outcome of
+	// the last reconciliation.
+	defaultResourceSummaryType = extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"desired": {
+				Type: "integer",
+			},
+			"applied": {
+				Type: "integer",
+			},
+			"errored": {
+				Type: "integer",
+			},
+		},
+	}
 	// additionalPrinterColumns specifies additional columns returned in Table output.
 	// See https://kubernetes.io/docs/reference/using-api/api-concepts/#receiving-resources-as-tables for details.
 	// Sample output for `kubectl get clusters`
@@ -0,0 +1,113 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/cel/ast"
+)
+
+// SchemaNodeID is the id of the instance node in the DependencyGraph. It is
+// the name the expressions use to refer to the instance.
+const SchemaNodeID = "schema"
+
+// DependencyGraph is an exported representation of the resources of a
+// resource graph definition, and of the dependencies between them, meant for
+// visualization tools.
+type DependencyGraph struct {
+	// Nodes are the instance, with the SchemaNodeID id, followed by the
+	// resources in topological order.
+	Nodes []DependencyGraphNode
+	// Edges are the dependencies between the nodes, derived from the
+	// references found in the resources expressions.
+	Edges []DependencyGraphEdge
+}
+
+// DependencyGraphNode is a node of the DependencyGraph.
+type DependencyGraphNode struct {
+	// ID is the resource id, or SchemaNodeID for the instance.
+	ID string
+	// GroupVersionKind is the GVK of the resource.
+	GroupVersionKind schema.GroupVersionKind
+	// ExternalRef is true if the resource is an external reference.
+	ExternalRef bool
+}
+
+// DependencyGraphEdge is an edge of the DependencyGraph. The To resource has
+// at least one expression referring to the From resource.
+type DependencyGraphEdge struct {
+	From string
+	To   string
+}
+
+// DependencyGraph returns the nodes and the edges of the graph. Unlike the DAG,
+// which is only concerned with the resources, the edges from the instance to
+// the resources referring to it are included. The edges are sorted by their
+// To node, in topological order, then by their From node.
+func (rgd *Graph) DependencyGraph() (*DependencyGraph, error) {
+	resourceIDs := append(slices.Clone(rgd.TopologicalOrder), SchemaNodeID)
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	inspector := ast.NewInspectorWithEnv(env, resourceIDs)
+
+	graph := &DependencyGraph{
+		Nodes: []DependencyGraphNode{{
+			ID: SchemaNodeID,
+			GroupVersionKind: schema.GroupVersionKind{
+				Group:   rgd.Instance.crd.Spec.Group,
+				Version: rgd.Instance.gvr.Version,
+				Kind:    rgd.Instance.crd.Spec.Names.Kind,
+			},
+		}},
+	}
+	for _, resourceID := range rgd.TopologicalOrder {
+		resource := rgd.Resources[resourceID]
+		graph.Nodes = append(graph.Nodes, DependencyGraphNode{
+			ID:               resourceID,
+			GroupVersionKind: resource.originalObject.GroupVersionKind(),
+			ExternalRef:      resource.isExternalRef,
+		})
+
+		expressions := slices.Clone(resource.includeWhenExpressions)
+		for _, resourceVariable := range resource.variables {
+			expressions = append(expressions, resourceVariable.Expressions...)
+		}
+
+		var dependencies []string
+		for _, expression := range expressions {
+			inspection, err := inspector.Inspect(expression)
+			if err != nil {
+				return nil, fmt.Errorf("failed to inspect expression %s of resource %s: %w", expression, resourceID, err)
+			}
+			for _, dependency := range inspection.ResourceDependencies {
+				if !slices.Contains(dependencies, dependency.ID) {
+					dependencies = append(dependencies, dependency.ID)
+				}
+			}
+		}
+		slices.Sort(dependencies)
+		for _, dependency := range dependencies {
+			graph.Edges = append(graph.Edges, DependencyGraphEdge{From: dependency, To: resourceID})
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kro-run/kro/pkg/graph/emulator"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+	"github.com/kro-run/kro/pkg/testutil/k8s"
+)
+
+func TestGraph_DependencyGraph(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	rgd := generator.NewResourceGraphDefinition("test-group",
+		generator.WithSchema(
+			"Network", "v1alpha1",
+			map[string]interface{}{
+				"name":              "string",
+				"cidrBlock":         "string",
+				"withSecurityGroup": "boolean",
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlocks": []interface{}{"${schema.spec.cidrBlock}"},
+			},
+		}, nil, nil),
+		generator.WithResource("subnet", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "Subnet",
+			"metadata": map[string]interface{}{
+				"name": "subnet",
+			},
+			"spec": map[string]interface{}{
+				"vpcID": "${vpc.status.vpcID}",
+			},
+		}, nil, nil),
+		generator.WithResource("securityGroup", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "SecurityGroup",
+			"metadata": map[string]interface{}{
+				"name": "secgroup",
+			},
+			"spec": map[string]interface{}{
+				"vpcID": "${subnet.spec.vpcID}",
+			},
+		}, nil, []string{"${schema.spec.withSecurityGroup}"}),
+	)
+
+	g, err := builder.NewResourceGraphDefinition(rgd)
+	require.NoError(t, err)
+
+	dependencyGraph, err := g.DependencyGraph()
+	require.NoError(t, err)
+
+	assert.Equal(t, []DependencyGraphNode{
+		{ID: SchemaNodeID, GroupVersionKind: schema.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "Network"}},
+		{ID: "vpc", GroupVersionKind: schema.GroupVersionKind{Group: "ec2.services.k8s.aws", Version: "v1alpha1", Kind: "VPC"}},
+		{ID: "subnet", GroupVersionKind: schema.GroupVersionKind{Group: "ec2.services.k8s.aws", Version: "v1alpha1", Kind: "Subnet"}},
+		{ID: "securityGroup", GroupVersionKind: schema.GroupVersionKind{Group: "ec2.services.k8s.aws", Version: "v1alpha1", Kind: "SecurityGroup"}},
+	}, dependencyGraph.Nodes)
+	assert.Equal(t, []DependencyGraphEdge{
+		{From: SchemaNodeID, To: "vpc"},
+		{From: "vpc", To: "subnet"},
+		{From: SchemaNodeID, To: "securityGroup"},
+		{From: "subnet", To: "securityGroup"},
+	}, dependencyGraph.Edges)
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -74,6 +75,9 @@ func (e *Emulator) GenerateDummyCR(gvk schema.GroupVersionKind,
 	cr.SetNamespace("default")
 	cr.SetResourceVersion(fmt.Sprintf("%d", e.rand.Intn(1000)))
 	cr.SetUID("dummy-uid")
+	// Server populated metadata, expressions can refer to it (e.g. to derive
+	// a name from the instance uid).
+	cr.SetCreationTimestamp(metav1.NewTime(time.Now()))
 	return cr, nil
 }
 
@@ -161,6 +165,11 @@ func (e *Emulator) generateString(schema *spec.Schema) string {
 	if len(schema.Enum) > 0 {
 		return schema.Enum[e.rand.Intn(len(schema.Enum))].(string)
 	}
+	// Use the default value if any, it is more likely to be a valid value
+	// for the expressions parsing the field, e.g. as a quantity.
+	if def, ok := schema.Default.(string); ok {
+		return def
+	}
 	return fmt.Sprintf("dummy-string-%d", e.rand.Intn(1000))
 }
 
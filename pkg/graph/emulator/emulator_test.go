@@ -483,6 +483,9 @@ func TestGenerateDummyCR(t *testing.T) {
 			assert.Equal(t, tt.gvk.Kind, cr.GetKind())
 			assert.Equal(t, "default", cr.GetNamespace())
 			assert.Equal(t, strings.ToLower(tt.gvk.Kind)+"-sample", cr.GetName())
+			assert.NotEmpty(t, cr.GetUID())
+			creationTimestamp := cr.GetCreationTimestamp()
+			assert.False(t, creationTimestamp.IsZero())
 
 			tt.validateOutput(t, cr.Object)
 		})
@@ -546,6 +549,18 @@ func TestGenerateValueWithIntOrString(t *testing.T) {
 
 }
 
+func TestGenerateStringWithDefault(t *testing.T) {
+	e := NewEmulator()
+
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:    []string{"string"},
+		Default: "1Gi",
+	}}
+	value, err := e.generateValue(schema)
+	require.NoError(t, err)
+	assert.Equal(t, "1Gi", value)
+}
+
 func TestGenerateValueWithPreserveUnknownFields(t *testing.T) {
 	e := NewEmulator()
 
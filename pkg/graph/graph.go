@@ -17,6 +17,7 @@ package graph
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/kro-run/kro/api/v1alpha1"
 	"github.com/kro-run/kro/pkg/graph/dag"
 	"github.com/kro-run/kro/pkg/runtime"
 )
@@ -33,10 +34,37 @@ type Graph struct {
 	Resources map[string]*Resource
 	// TopologicalOrder is the topological order of the resources in the resource graph definition.
 	TopologicalOrder []string
+	// ReadyGate is the list of resource IDs gating the instance readiness.
+	// When empty, all the resources gate the instance readiness.
+	ReadyGate []string
+	// ReadyExpression is the expression, with '${}' removed, deciding whether
+	// the instance is ready. Empty when the resource graph definition doesn't
+	// set one.
+	ReadyExpression string
+	// Propagation selects the instance labels and annotations copied onto the
+	// resources. Nil when none are.
+	Propagation *v1alpha1.Propagation
+	// readyExpressionDependencies are the resources the ready expression
+	// depends on.
+	readyExpressionDependencies []string
+	// Warnings are the non fatal issues found while building the graph, e.g.
+	// ignored unknown schema markers.
+	Warnings []string
+	// overlayField is the path of the instance spec field selecting the
+	// overlay variant.
+	overlayField string
+	// overlays are the graphs of the overlay variants, keyed by the value of
+	// the overlay field selecting them.
+	overlays map[string]*Graph
 }
 
 // NewGraphRuntime creates a new runtime resource graph definition from the resource graph definition instance.
 func (rgd *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runtime.ResourceGraphDefinitionRuntime, error) {
+	// Instances selecting an overlay variant are resolved with its graph.
+	if variant, ok := rgd.overlayFor(newInstance); ok {
+		return variant.NewGraphRuntime(newInstance)
+	}
+
 	// we need to copy the resources to the runtime resources, mainly focusing
 	// on the variables and dependencies.
 	resources := make(map[string]runtime.Resource)
@@ -46,7 +74,17 @@ func (rgd *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runt
 
 	instance := rgd.Instance.DeepCopy()
 	instance.originalObject = newInstance
-	rt, err := runtime.NewResourceGraphDefinitionRuntime(instance, resources, rgd.TopologicalOrder)
+
+	var opts []runtime.Option
+	if len(instance.computedDefaults) > 0 {
+		opts = append(opts, runtime.WithSchemaDefaulter(func(obj *unstructured.Unstructured) error {
+			return applyComputedDefaults(obj, instance.computedDefaults)
+		}))
+	}
+	if rgd.ReadyExpression != "" {
+		opts = append(opts, runtime.WithReadyExpression(rgd.ReadyExpression, rgd.readyExpressionDependencies))
+	}
+	rt, err := runtime.NewResourceGraphDefinitionRuntime(instance, resources, rgd.TopologicalOrder, opts...)
 	if err != nil {
 		return nil, err
 	}
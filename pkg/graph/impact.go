@@ -0,0 +1,90 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+// InstanceImpact describes how a change of a resource graph definition
+// affects one of its instances.
+type InstanceImpact struct {
+	// Namespace and Name identify the instance.
+	Namespace string
+	Name      string
+	// NewlyFailing is true if the instance resources can be resolved with the
+	// old resource graph definition, but not with the new one.
+	NewlyFailing bool
+	// Err is the error resolving the instance resources with the new resource
+	// graph definition, if any.
+	Err error
+	// Changed is true if the resolved resources differ between the old and
+	// the new resource graph definitions.
+	Changed bool
+}
+
+// Affected returns true if the change of the resource graph definition has
+// an effect on the instance.
+func (i InstanceImpact) Affected() bool {
+	return i.NewlyFailing || i.Changed
+}
+
+// ImpactOfRGDChange previews the impact of replacing oldRGD with newRGD on
+// the given instances. Each instance is resolved offline (see RenderBundle)
+// with both resource graph definitions, and the results are compared.
+//
+// An error is returned if either resource graph definition is invalid. Errors
+// resolving an instance are reported in its InstanceImpact.
+func (b *Builder) ImpactOfRGDChange(
+	oldRGD, newRGD *v1alpha1.ResourceGraphDefinition,
+	instances []*unstructured.Unstructured,
+) ([]InstanceImpact, error) {
+	oldGraph, err := b.NewResourceGraphDefinition(oldRGD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build old resource graph definition: %w", err)
+	}
+	newGraph, err := b.NewResourceGraphDefinition(newRGD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build new resource graph definition: %w", err)
+	}
+
+	impacts := make([]InstanceImpact, 0, len(instances))
+	for _, instance := range instances {
+		impact := InstanceImpact{
+			Namespace: instance.GetNamespace(),
+			Name:      instance.GetName(),
+		}
+
+		oldBundle, oldErr := RenderBundle(oldGraph, instance.DeepCopy(), nil)
+		newBundle, newErr := RenderBundle(newGraph, instance.DeepCopy(), nil)
+		impact.Err = newErr
+		switch {
+		case newErr != nil:
+			impact.NewlyFailing = oldErr == nil
+		case oldErr != nil:
+			// The instance couldn't be resolved before, it can now.
+			impact.Changed = true
+		default:
+			impact.Changed = !bytes.Equal(oldBundle, newBundle)
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/emulator"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+	"github.com/kro-run/kro/pkg/testutil/k8s"
+)
+
+func TestImpactOfRGDChange(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	schema := generator.WithSchema(
+		"Network", "v1alpha1",
+		map[string]interface{}{
+			"name": "string",
+		},
+		nil,
+	)
+	vpc := generator.WithResource("vpc", map[string]interface{}{
+		"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+		"kind":       "VPC",
+		"metadata": map[string]interface{}{
+			"name": "${schema.spec.name}-vpc",
+		},
+		"spec": map[string]interface{}{
+			"cidrBlocks": []interface{}{"10.0.0.0/16"},
+		},
+	}, nil, nil)
+	oldRGD := generator.NewResourceGraphDefinition("testrgd", schema, vpc)
+	// The security group can only be resolved once the vpc is applied.
+	newRGD := generator.NewResourceGraphDefinition("testrgd", schema, vpc,
+		generator.WithResource("securitygroup", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "SecurityGroup",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}-sg",
+			},
+			"spec": map[string]interface{}{
+				"vpcID": "${vpc.status.vpcID}",
+			},
+		}, nil, []string{"${schema.spec.name == 'with-sg'}"}),
+	)
+
+	newInstance := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "Network",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"name": name,
+			},
+		}}
+	}
+
+	impacts, err := builder.ImpactOfRGDChange(oldRGD, newRGD, []*unstructured.Unstructured{
+		newInstance("prod"),
+		newInstance("with-sg"),
+	})
+	require.NoError(t, err)
+	require.Len(t, impacts, 2)
+
+	assert.Equal(t, "prod", impacts[0].Name)
+	assert.False(t, impacts[0].Affected())
+	assert.NoError(t, impacts[0].Err)
+
+	assert.Equal(t, "with-sg", impacts[1].Name)
+	assert.True(t, impacts[1].Affected())
+	assert.True(t, impacts[1].NewlyFailing)
+	require.Error(t, impacts[1].Err)
+	assert.Contains(t, impacts[1].Err.Error(), "vpc.status.vpcID")
+}
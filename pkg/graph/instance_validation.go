@@ -0,0 +1,88 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+// ValidateInstance validates the spec of the instance against the schema
+// generated from the resource graph definition, without a cluster. The spec
+// goes through the same steps as in the API server: unknown fields are
+// pruned, defaults are applied, then the schema (types, required fields,
+// enums, bounds, patterns, formats) and its CEL rules are validated. The
+// returned error aggregates the errors of every invalid field, with its path.
+func ValidateInstance(rgd *v1alpha1.ResourceGraphDefinition, instance *unstructured.Unstructured) error {
+	specProps, err := buildInstanceSpecSchema(rgd.Spec.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to build instance spec schema: %w", err)
+	}
+
+	// The spec is nested under its own property, for the errors to report
+	// field paths relative to the instance.
+	instanceProps := &extv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]extv1.JSONSchemaProps{"spec": *specProps},
+	}
+	internalProps := &apiextensions.JSONSchemaProps{}
+	if err := extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(instanceProps, internalProps, nil); err != nil {
+		return fmt.Errorf("failed to convert instance schema: %w", err)
+	}
+	structural, err := structuralschema.NewStructural(internalProps)
+	if err != nil {
+		return fmt.Errorf("failed to build structural instance schema: %w", err)
+	}
+	validator, _, err := validation.NewSchemaValidator(internalProps)
+	if err != nil {
+		return fmt.Errorf("failed to build instance schema validator: %w", err)
+	}
+
+	// The instance is left untouched, only a copy of its spec is validated.
+	obj := map[string]interface{}{"spec": map[string]interface{}{}}
+	if s, ok := instance.Object["spec"].(map[string]interface{}); ok {
+		obj["spec"] = k8sruntime.DeepCopyJSON(s)
+	}
+
+	var errs field.ErrorList
+	unknownFields := pruning.PruneWithOptions(obj, structural, true, structuralschema.UnknownFieldPathOptions{
+		TrackUnknownFieldPaths: true,
+	})
+	for _, path := range unknownFields {
+		errs = append(errs, field.NotFound(field.NewPath(path), path[strings.LastIndex(path, ".")+1:]))
+	}
+	defaulting.Default(obj, structural)
+	errs = append(errs, validation.ValidateCustomResource(nil, obj, validator)...)
+	if celValidator := cel.NewValidator(structural, true, celconfig.PerCallLimit); celValidator != nil {
+		celErrs, _ := celValidator.Validate(context.TODO(), nil, structural, obj, nil, celconfig.RuntimeCELCostBudget)
+		errs = append(errs, celErrs...)
+	}
+	return errs.ToAggregate()
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+func TestValidateInstance(t *testing.T) {
+	rgd := generator.NewResourceGraphDefinition("test-rgd",
+		generator.WithSchema(
+			"WebApp", "v1alpha1",
+			map[string]interface{}{
+				"name":     "string | required=true",
+				"tier":     "string | enum=\"frontend,backend\" default=\"frontend\"",
+				"replicas": "integer | minimum=1 maximum=10 default=1",
+				"email":    "string | pattern=\"^.+@.+$\"",
+			},
+			nil,
+		),
+		generator.WithValidation("self.tier != 'backend' || self.replicas >= 2", "backends need at least two replicas"),
+	)
+
+	tests := []struct {
+		name        string
+		spec        map[string]interface{}
+		expectedErr []string
+	}{
+		{
+			name: "valid instance",
+			spec: map[string]interface{}{"name": "app", "tier": "backend", "replicas": int64(3), "email": "team@example.com"},
+		},
+		{
+			name: "defaults are valid",
+			spec: map[string]interface{}{"name": "app"},
+		},
+		{
+			name:        "missing required field",
+			spec:        map[string]interface{}{"tier": "backend"},
+			expectedErr: []string{"spec.name: Required value"},
+		},
+		{
+			name:        "enum violation",
+			spec:        map[string]interface{}{"name": "app", "tier": "database"},
+			expectedErr: []string{`spec.tier: Unsupported value: "database"`},
+		},
+		{
+			name:        "out of bounds",
+			spec:        map[string]interface{}{"name": "app", "replicas": int64(20)},
+			expectedErr: []string{"spec.replicas: Invalid value: 20: spec.replicas in body should be less than or equal to 10"},
+		},
+		{
+			name:        "pattern violation",
+			spec:        map[string]interface{}{"name": "app", "email": "team"},
+			expectedErr: []string{`spec.email: Invalid value: "team": spec.email in body should match`},
+		},
+		{
+			name:        "validation rule violation",
+			spec:        map[string]interface{}{"name": "app", "tier": "backend"},
+			expectedErr: []string{"spec: Invalid value: \"object\": backends need at least two replicas"},
+		},
+		{
+			name:        "unknown field",
+			spec:        map[string]interface{}{"name": "app", "size": "large"},
+			expectedErr: []string{"spec.size: Not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "WebApp",
+				"spec":       tt.spec,
+			}}
+			instance := original.DeepCopy()
+			err := ValidateInstance(rgd, instance)
+			// The instance itself isn't defaulted.
+			assert.Equal(t, original, instance)
+			if len(tt.expectedErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectedErr {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidateInstanceArrayOfObjects(t *testing.T) {
+	rgd := generator.NewResourceGraphDefinition("test-rgd",
+		generator.WithSchema(
+			"WebApp", "v1alpha1",
+			map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{
+						"name":     "string | required=true",
+						"port":     "integer | minimum=1 maximum=65535",
+						"protocol": "string | enum=\"TCP,UDP\" default=\"TCP\"",
+					},
+				},
+			},
+			nil,
+		),
+	)
+
+	tests := []struct {
+		name        string
+		ports       []interface{}
+		expectedErr []string
+	}{
+		{
+			name: "valid items",
+			ports: []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(80)},
+				map[string]interface{}{"name": "dns", "port": int64(53), "protocol": "UDP"},
+			},
+		},
+		{
+			name: "invalid items",
+			ports: []interface{}{
+				map[string]interface{}{"port": int64(80)},
+				map[string]interface{}{"name": "dns", "port": int64(70000), "protocol": "SCTP"},
+			},
+			expectedErr: []string{
+				"spec.ports[0].name: Required value",
+				"spec.ports[1].port: Invalid value: 70000",
+				`spec.ports[1].protocol: Unsupported value: "SCTP"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "WebApp",
+				"spec":       map[string]interface{}{"ports": tt.ports},
+			}}
+			err := ValidateInstance(rgd, instance)
+			if len(tt.expectedErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectedErr {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidateInstanceMutuallyExclusive(t *testing.T) {
+	rgd := generator.NewResourceGraphDefinition("test-rgd",
+		generator.WithSchema(
+			"WebApp", "v1alpha1",
+			map[string]interface{}{
+				"image":     "string",
+				"image-ref": "string",
+			},
+			nil,
+		),
+		generator.WithMutuallyExclusive("image", "image-ref"),
+	)
+
+	newInstance := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "WebApp",
+			"spec":       spec,
+		}}
+	}
+	require.NoError(t, ValidateInstance(rgd, newInstance(map[string]interface{}{"image-ref": "app"})))
+	err := ValidateInstance(rgd, newInstance(map[string]interface{}{"image": "nginx", "image-ref": "app"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most one of image, image-ref can be set")
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+// buildOverlays builds a graph for each variant of the overlays, from the
+// resource graph definition with the variant patches merged into its resource
+// templates. The resource graph definition must not have overlays itself.
+func (b *Builder) buildOverlays(
+	rgd *v1alpha1.ResourceGraphDefinition,
+	overlays *v1alpha1.Overlays,
+	instance *Resource,
+) (map[string]*Graph, error) {
+	if overlays == nil {
+		return nil, nil
+	}
+	if err := validateOverlayField(instance.crd, overlays.Field); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(overlays.Variants))
+	for value := range overlays.Variants {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+
+	variants := make(map[string]*Graph, len(values))
+	for _, value := range values {
+		variant := rgd.DeepCopy()
+		if err := applyOverlay(variant, overlays.Variants[value]); err != nil {
+			return nil, fmt.Errorf("invalid overlay %q: %w", value, err)
+		}
+		g, err := b.NewResourceGraphDefinition(variant)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build overlay %q: %w", value, err)
+		}
+		variants[value] = g
+	}
+	return variants, nil
+}
+
+// validateOverlayField makes sure the given dotted path points to a string
+// field of the instance spec.
+func validateOverlayField(crd *extv1.CustomResourceDefinition, field string) error {
+	if field == "" {
+		return fmt.Errorf("overlays field can't be empty")
+	}
+	current := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	for _, part := range strings.Split(field, ".") {
+		property, ok := current.Properties[part]
+		if !ok {
+			return fmt.Errorf("overlays field %q is not a spec field", field)
+		}
+		current = property
+	}
+	if current.Type != "string" {
+		return fmt.Errorf("overlays field %q must be a string, got %s", field, current.Type)
+	}
+	return nil
+}
+
+// applyOverlay merges the patches of an overlay variant into the templates of
+// the resources they are keyed by.
+func applyOverlay(rgd *v1alpha1.ResourceGraphDefinition, patches map[string]k8sruntime.RawExtension) error {
+	ids := make([]string, 0, len(patches))
+	for id := range patches {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		index := slices.IndexFunc(rgd.Spec.Resources, func(r *v1alpha1.Resource) bool {
+			return r.ID == id
+		})
+		if index < 0 {
+			return fmt.Errorf("resource %q is not defined in the resource graph definition", id)
+		}
+		resource := rgd.Spec.Resources[index]
+		if resource.ExternalRef != nil {
+			return fmt.Errorf("resource %q is an external reference, it has no template to patch", id)
+		}
+
+		var template, patch map[string]interface{}
+		if err := json.Unmarshal(resource.Template.Raw, &template); err != nil {
+			return fmt.Errorf("failed to unmarshal template of resource %q: %w", id, err)
+		}
+		if err := json.Unmarshal(patches[id].Raw, &patch); err != nil {
+			return fmt.Errorf("failed to unmarshal patch of resource %q: %w", id, err)
+		}
+		raw, err := json.Marshal(mergePatch(template, patch))
+		if err != nil {
+			return fmt.Errorf("failed to marshal template of resource %q: %w", id, err)
+		}
+		resource.Template.Raw = raw
+		resource.Template.Object = nil
+	}
+	return nil
+}
+
+// mergePatch merges the patch into the target following the JSON merge patch
+// semantics (RFC 7386): objects are merged recursively, a null value removes
+// the field, and any other value replaces the target one. Like a strategic
+// merge patch, lists of objects keyed by name, e.g. containers or ports, are
+// merged by name instead of being replaced. The target is modified in place.
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchList, ok := value.([]interface{}); ok {
+			targetList, _ := target[key].([]interface{})
+			if merged, ok := mergeNamedList(targetList, patchList); ok {
+				target[key] = merged
+				continue
+			}
+		}
+		patchObject, ok := value.(map[string]interface{})
+		if !ok {
+			target[key] = value
+			continue
+		}
+		targetObject, _ := target[key].(map[string]interface{})
+		target[key] = mergePatch(targetObject, patchObject)
+	}
+	return target
+}
+
+// mergeNamedList merges the patch items into the target items with the same
+// name, and appends the other ones. It returns false if any item of either
+// list isn't an object with a name, the patch list then replaces the target
+// one.
+func mergeNamedList(target, patch []interface{}) ([]interface{}, bool) {
+	if len(target) == 0 {
+		return nil, false
+	}
+	indexes := make(map[string]int, len(target))
+	for i, item := range target {
+		name, ok := itemName(item)
+		if !ok {
+			return nil, false
+		}
+		indexes[name] = i
+	}
+	for _, item := range patch {
+		if _, ok := itemName(item); !ok {
+			return nil, false
+		}
+	}
+
+	for _, item := range patch {
+		name, _ := itemName(item)
+		patchObject := item.(map[string]interface{})
+		if i, ok := indexes[name]; ok {
+			target[i] = mergePatch(target[i].(map[string]interface{}), patchObject)
+			continue
+		}
+		indexes[name] = len(target)
+		target = append(target, patchObject)
+	}
+	return target, true
+}
+
+// itemName returns the name of a list item, if it is an object with a string
+// name field.
+func itemName(item interface{}) (string, bool) {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, ok := object["name"].(string)
+	return name, ok
+}
+
+// overlayFor returns the graph of the overlay variant selected by the
+// instance, if any.
+func (rgd *Graph) overlayFor(instance *unstructured.Unstructured) (*Graph, bool) {
+	if len(rgd.overlays) == 0 {
+		return nil, false
+	}
+	path := append([]string{"spec"}, strings.Split(rgd.overlayField, ".")...)
+	value, found, err := unstructured.NestedString(instance.Object, path...)
+	if err != nil || !found {
+		return nil, false
+	}
+	variant, ok := rgd.overlays[value]
+	return variant, ok
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/emulator"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+	"github.com/kro-run/kro/pkg/testutil/k8s"
+)
+
+func TestGraphBuilder_Overlays(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	schema := generator.WithSchema(
+		"Network", "v1alpha1",
+		map[string]interface{}{
+			"name":        "string",
+			"environment": "string | default=dev",
+			"replicas":    "integer",
+		},
+		nil,
+	)
+	vpc := generator.WithResource("vpc", map[string]interface{}{
+		"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+		"kind":       "VPC",
+		"metadata": map[string]interface{}{
+			"name": "${schema.spec.name}",
+		},
+		"spec": map[string]interface{}{
+			"cidrBlocks":         []interface{}{"10.0.0.0/16"},
+			"enableDNSHostnames": false,
+			"enableDNSSupport":   true,
+		},
+	}, nil, nil)
+	prod := generator.WithOverlay("environment", "prod", map[string]map[string]interface{}{
+		"vpc": {
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name + '-prod'}",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlocks":         []interface{}{"10.1.0.0/16", "10.2.0.0/16"},
+				"enableDNSHostnames": true,
+				"enableDNSSupport":   nil,
+			},
+		},
+	})
+
+	resolveVPC := func(t *testing.T, g *Graph, environment string) *unstructured.Unstructured {
+		rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name":        "network",
+				"environment": environment,
+			},
+		}})
+		require.NoError(t, err)
+		_, err = rt.Synchronize()
+		require.NoError(t, err)
+		resource, _ := rt.GetResource("vpc")
+		require.NotNil(t, resource)
+		return resource
+	}
+
+	t.Run("the selected variant patches the template", func(t *testing.T) {
+		g, err := builder.NewResourceGraphDefinition(generator.NewResourceGraphDefinition("test-group", schema, vpc, prod))
+		require.NoError(t, err)
+
+		dev := resolveVPC(t, g, "dev")
+		assert.Equal(t, "network", dev.GetName())
+		assert.Equal(t, map[string]interface{}{
+			"cidrBlocks":         []interface{}{"10.0.0.0/16"},
+			"enableDNSHostnames": false,
+			"enableDNSSupport":   true,
+		}, dev.Object["spec"])
+
+		prod := resolveVPC(t, g, "prod")
+		assert.Equal(t, "network-prod", prod.GetName())
+		assert.Equal(t, map[string]interface{}{
+			"cidrBlocks":         []interface{}{"10.1.0.0/16", "10.2.0.0/16"},
+			"enableDNSHostnames": true,
+		}, prod.Object["spec"])
+	})
+
+	tests := []struct {
+		name    string
+		overlay generator.ResourceGraphDefinitionOption
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			overlay: generator.WithOverlay("region", "eu", nil),
+			wantErr: `overlays field "region" is not a spec field`,
+		},
+		{
+			name:    "field is not a string",
+			overlay: generator.WithOverlay("replicas", "3", nil),
+			wantErr: `overlays field "replicas" must be a string, got integer`,
+		},
+		{
+			name: "unknown resource",
+			overlay: generator.WithOverlay("environment", "prod", map[string]map[string]interface{}{
+				"subnet": {},
+			}),
+			wantErr: `invalid overlay "prod": resource "subnet" is not defined in the resource graph definition`,
+		},
+		{
+			name: "invalid patched template",
+			overlay: generator.WithOverlay("environment", "prod", map[string]map[string]interface{}{
+				"vpc": {
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.unknown}",
+					},
+				},
+			}),
+			wantErr: `failed to build overlay "prod"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := builder.NewResourceGraphDefinition(generator.NewResourceGraphDefinition("test-group", schema, vpc, tt.overlay))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "a",
+		"b": map[string]interface{}{
+			"c": "c",
+			"d": []interface{}{"d"},
+		},
+		"e": "e",
+	}
+	patch := map[string]interface{}{
+		"b": map[string]interface{}{
+			"d": []interface{}{"x", "y"},
+			"f": map[string]interface{}{"g": "g", "h": nil},
+		},
+		"e": nil,
+	}
+	assert.Equal(t, map[string]interface{}{
+		"a": "a",
+		"b": map[string]interface{}{
+			"c": "c",
+			"d": []interface{}{"x", "y"},
+			"f": map[string]interface{}{"g": "g"},
+		},
+	}, mergePatch(target, patch))
+}
+
+func TestMergePatchNamedLists(t *testing.T) {
+	target := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "app:v1", "args": []interface{}{"--debug"}},
+			map[string]interface{}{"name": "sidecar", "image": "sidecar:v1"},
+		},
+		"args": []interface{}{"a", "b"},
+	}
+	patch := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "app:v2", "args": nil},
+			map[string]interface{}{"name": "metrics", "image": "metrics:v1"},
+		},
+		"args": []interface{}{"c"},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "app:v2"},
+			map[string]interface{}{"name": "sidecar", "image": "sidecar:v1"},
+			map[string]interface{}{"name": "metrics", "image": "metrics:v1"},
+		},
+		"args": []interface{}{"c"},
+	}, mergePatch(target, patch))
+}
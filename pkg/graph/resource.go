@@ -15,13 +15,16 @@
 package graph
 
 import (
+	"maps"
 	"slices"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 
+	"github.com/kro-run/kro/api/v1alpha1"
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
 
@@ -63,6 +66,9 @@ type Resource struct {
 	// readyWhenExpressions is a list of the expressions that need to be evaluated
 	// before the resource is considered ready.
 	readyWhenExpressions []string
+	// readyWhenJSONPaths is a list of JSONPath checks that need to match the
+	// observed resource before it is considered ready.
+	readyWhenJSONPaths []v1alpha1.ReadyWhenJSONPath
 	// includeWhenExpressions is a list of the expresisons that need to be evaluated
 	// to decide whether to create a resource graph definition or not
 	includeWhenExpressions []string
@@ -75,6 +81,19 @@ type Resource struct {
 	order int
 	// isExternalRef indicates if the resource should only be read and not created/updated
 	isExternalRef bool
+	// deleteWithInstance indicates if an external reference should be deleted
+	// along with the instance.
+	deleteWithInstance bool
+	// reconcileOnce indicates if the resource should only be created, and
+	// left alone once it exists.
+	reconcileOnce bool
+	// deletionPropagation is the propagation policy used to delete the
+	// resource, empty to use the controller default.
+	deletionPropagation metav1.DeletionPropagation
+	// computedDefaults maps the instance spec fields to the expressions
+	// computing their value when they are left unset. Only set on the
+	// instance resource.
+	computedDefaults map[string]string
 }
 
 // GetDependencies returns the dependencies of the resource.
@@ -151,6 +170,11 @@ func (r *Resource) GetReadyWhenExpressions() []string {
 	return r.readyWhenExpressions
 }
 
+// GetReadyWhenJSONPaths returns the readyWhen JSONPath checks of the resource.
+func (r *Resource) GetReadyWhenJSONPaths() []v1alpha1.ReadyWhenJSONPath {
+	return r.readyWhenJSONPaths
+}
+
 // GetIncludeWhenExpressions returns the condition expressions of the resource.
 func (r *Resource) GetIncludeWhenExpressions() []string {
 	return r.includeWhenExpressions
@@ -166,6 +190,24 @@ func (r *Resource) IsExternalRef() bool {
 	return r.isExternalRef
 }
 
+// DeleteWithInstance returns whether the external reference should be deleted
+// when the instance is deleted.
+func (r *Resource) DeleteWithInstance() bool {
+	return r.deleteWithInstance
+}
+
+// ReconcileOnce returns whether the resource should only be created, and never
+// updated once it exists.
+func (r *Resource) ReconcileOnce() bool {
+	return r.reconcileOnce
+}
+
+// DeletionPropagation returns the propagation policy used to delete the
+// resource, or an empty policy to use the controller default.
+func (r *Resource) DeletionPropagation() metav1.DeletionPropagation {
+	return r.deletionPropagation
+}
+
 // DeepCopy returns a deep copy of the resource.
 func (r *Resource) DeepCopy() *Resource {
 	return &Resource{
@@ -177,8 +219,13 @@ func (r *Resource) DeepCopy() *Resource {
 		variables:              slices.Clone(r.variables),
 		dependencies:           slices.Clone(r.dependencies),
 		readyWhenExpressions:   slices.Clone(r.readyWhenExpressions),
+		readyWhenJSONPaths:     slices.Clone(r.readyWhenJSONPaths),
 		includeWhenExpressions: slices.Clone(r.includeWhenExpressions),
 		namespaced:             r.namespaced,
 		isExternalRef:          r.isExternalRef,
+		deleteWithInstance:     r.deleteWithInstance,
+		reconcileOnce:          r.reconcileOnce,
+		deletionPropagation:    r.deletionPropagation,
+		computedDefaults:       maps.Clone(r.computedDefaults),
 	}
 }
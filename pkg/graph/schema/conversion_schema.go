@@ -15,6 +15,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"fmt"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -46,7 +47,6 @@ func ConvertJSONSchemaPropsToSpecSchema(props *extv1.JSONSchemaProps) (*spec.Sch
 			Schema:           spec.SchemaURL(props.Schema),
 			Title:            props.Title,
 			Description:      props.Description,
-			Default:          props.Default,
 			Format:           props.Format,
 			Maximum:          props.Maximum,
 			ExclusiveMaximum: props.ExclusiveMaximum,
@@ -76,6 +76,21 @@ func ConvertJSONSchemaPropsToSpecSchema(props *extv1.JSONSchemaProps) (*spec.Sch
 		schema.SchemaProps.Type = []string{props.Type}
 	}
 
+	if props.Default != nil {
+		if err := json.Unmarshal(props.Default.Raw, &schema.Default); err != nil {
+			return nil, fmt.Errorf("error converting default value: %w", err)
+		}
+	}
+
+	if len(props.Enum) > 0 {
+		schema.Enum = make([]interface{}, len(props.Enum))
+		for i, e := range props.Enum {
+			if err := json.Unmarshal(e.Raw, &schema.Enum[i]); err != nil {
+				return nil, fmt.Errorf("error converting enum value at index %d: %w", i, err)
+			}
+		}
+	}
+
 	if props.Items != nil {
 		if props.Items.Schema != nil {
 			itemsSchema, err := ConvertJSONSchemaPropsToSpecSchema(props.Items.Schema)
@@ -0,0 +1,56 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"slices"
+
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// topLevelFields are validated by the API server, whatever the resource.
+var topLevelFields = []string{"apiVersion", "kind", "metadata"}
+
+// ValidateObject validates the object against its OpenAPI schema with the
+// schema validator the API server uses for custom resources. It reports type
+// mismatches, missing required fields, values outside of an enum or of the
+// schema bounds, and strings not matching their pattern or format, along with
+// their field path.
+//
+// This is a cheap check meant to catch obvious template errors before they
+// reach the API server, it doesn't replace the API server validation (unknown
+// fields and CEL rules are not checked).
+func ValidateObject(schema *spec.Schema, obj map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+
+	// Leave the top level fields out, the published schemas don't always
+	// describe them the way the API server handles them.
+	s := *schema
+	s.Required = slices.DeleteFunc(slices.Clone(s.Required), func(name string) bool {
+		return slices.Contains(topLevelFields, name)
+	})
+	object := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if !slices.Contains(topLevelFields, key) {
+			object[key] = value
+		}
+	}
+
+	validator := validation.NewSchemaValidatorFromOpenAPI(&s)
+	return validation.ValidateCustomResource(nil, object, validator).ToAggregate()
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestValidateObject(t *testing.T) {
+	deploymentSchema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"apiVersion": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"kind":       {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"metadata":   {SchemaProps: spec.SchemaProps{Type: []string{"object"}}},
+				"spec": {SchemaProps: spec.SchemaProps{
+					Type:     []string{"object"},
+					Required: []string{"selector"},
+					Properties: map[string]spec.Schema{
+						"replicas": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+						"selector": {SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+							},
+						}},
+						"strategy": {SchemaProps: spec.SchemaProps{
+							Type: []string{"string"},
+							Enum: []interface{}{"Recreate", "RollingUpdate"},
+						}},
+						"revision": {SchemaProps: spec.SchemaProps{
+							Type: []string{"string"},
+							Enum: []interface{}{"1", "2"},
+						}},
+						"owner": {SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "email",
+						}},
+						"ports": {SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{Schema: &spec.Schema{
+								SchemaProps: spec.SchemaProps{
+									Type: []string{"object"},
+									Properties: map[string]spec.Schema{
+										"targetPort": {
+											VendorExtensible: spec.VendorExtensible{
+												Extensions: spec.Extensions{"x-kubernetes-int-or-string": true},
+											},
+										},
+									},
+								},
+							}},
+						}},
+						"template": {
+							SchemaProps: spec.SchemaProps{
+								Type: []string{"object"},
+								Properties: map[string]spec.Schema{
+									"image": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+								},
+							},
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{"x-kubernetes-preserve-unknown-fields": true},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		obj     map[string]interface{}
+		wantErr []string
+	}{
+		{
+			name: "valid object",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "test"},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"selector": map[string]interface{}{"app": "test"},
+					"strategy": "Recreate",
+					"revision": "1",
+					"owner":    "team@example.com",
+					"ports": []interface{}{
+						map[string]interface{}{"targetPort": "http"},
+						map[string]interface{}{"targetPort": int64(8080)},
+					},
+					"template": map[string]interface{}{
+						"image":   "nginx",
+						"unknown": "preserved",
+					},
+				},
+			},
+		},
+		{
+			name: "structurally invalid object",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec": map[string]interface{}{
+					"replicas": "three",
+					"strategy": "BlueGreen",
+					"revision": int64(1),
+					"owner":    "team",
+				},
+			},
+			wantErr: []string{
+				"spec.selector: Required value",
+				"spec.replicas in body must be of type integer",
+				`spec.strategy: Unsupported value: "BlueGreen"`,
+				`spec.revision: Unsupported value: 1: supported values: "1", "2"`,
+				`spec.owner: Invalid value: "team": spec.owner in body must be of type email`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateObject(deploymentSchema, tt.obj)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				for _, want := range tt.wantErr {
+					assert.Contains(t, err.Error(), want)
+				}
+			}
+		})
+	}
+}
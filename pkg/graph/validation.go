@@ -17,8 +17,11 @@ package graph
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/metadata"
 )
 
 var (
@@ -161,6 +164,26 @@ func validateKubernetesObjectStructure(obj map[string]interface{}) error {
 	return nil
 }
 
+// validateReservedLabels checks that the labels of the given object don't
+// include the labels kro sets on the resources it manages. Those would be
+// silently overwritten, and could make kro lose track of the resource.
+func validateReservedLabels(obj map[string]interface{}) error {
+	objMeta, _ := obj["metadata"].(map[string]interface{})
+	labels, _ := objMeta["labels"].(map[string]interface{})
+
+	var reserved []string
+	for key := range labels {
+		if metadata.IsReservedLabel(key) {
+			reserved = append(reserved, key)
+		}
+	}
+	if len(reserved) > 0 {
+		sort.Strings(reserved)
+		return fmt.Errorf("labels %s are reserved by kro", strings.Join(reserved, ", "))
+	}
+	return nil
+}
+
 // validateKubernetesVersion checks if the given version is a valid Kubernetes
 // version. e.g v1, v1alpha1, v1beta1..
 func validateKubernetesVersion(version string) error {
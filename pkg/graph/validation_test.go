@@ -215,6 +215,60 @@ func TestValidateKubernetesObjectStructure(t *testing.T) {
 	}
 }
 
+func TestValidateReservedLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]interface{}
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "No labels",
+			wantErr: false,
+		},
+		{
+			name: "Author labels",
+			labels: map[string]interface{}{
+				"app":                 "web",
+				"kro.run/team":        "platform",
+				"team.example.com/id": "${schema.spec.team}",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Reserved labels",
+			labels: map[string]interface{}{
+				"app":                   "web",
+				"kro.run/owned":         "false",
+				"kro.run/instance-name": "${schema.spec.name}",
+			},
+			wantErr: true,
+			errMsg:  "labels kro.run/instance-name, kro.run/owned are reserved by kro",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objMeta := map[string]interface{}{"name": "test"}
+			if tt.labels != nil {
+				objMeta["labels"] = tt.labels
+			}
+			err := validateReservedLabels(map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   objMeta,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateReservedLabels() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("validateReservedLabels() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
 func TestValidateKubernetesVersion(t *testing.T) {
 	tests := []struct {
 		version    string
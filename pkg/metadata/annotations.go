@@ -0,0 +1,233 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationKROPrefix is the annotation key prefix used by KRO.
+	AnnotationKROPrefix = LabelKROPrefix
+
+	// ReconcileAnnotation can be set on an instance to force a reconcile
+	// without changing its spec. Any change to its value (typically a
+	// timestamp) enqueues the instance, even if its generation didn't change.
+	ReconcileAnnotation = AnnotationKROPrefix + "reconcile"
+
+	// ApplyOnlyAnnotation restricts the reconciliation of an instance to a
+	// comma separated list of resource IDs (e.g "deployment,service"). The
+	// other resources are only observed, they are never created nor updated.
+	// This is meant for debugging and incident response, and should be
+	// removed once done. IDs that are not resources of the graph are reported
+	// on the status of the instance.
+	ApplyOnlyAnnotation = AnnotationKROPrefix + "apply-only"
+
+	// SuspendAnnotation, when set to "true" on an instance, keeps its
+	// workloads suspended: Deployments and StatefulSets are scaled to zero
+	// and CronJobs are suspended, while the other resources are reconciled
+	// as usual. Removing the annotation restores the templated values, or
+	// the replicas the workloads had before when they aren't templated.
+	SuspendAnnotation = AnnotationKROPrefix + "suspend"
+
+	// SuspendedReplicasAnnotation is set by kro on the Deployments and
+	// StatefulSets it scales to zero while their instance is suspended. It
+	// holds the number of replicas to restore once the instance is resumed,
+	// when the template doesn't set it.
+	SuspendedReplicasAnnotation = AnnotationKROPrefix + "suspended-replicas"
+
+	// ServiceAccountAnnotation names a service account, in the namespace of
+	// the instance, that kro impersonates to create, update and delete the
+	// resources of the instance. The service account must be allowed by the
+	// controller configuration.
+	ServiceAccountAnnotation = AnnotationKROPrefix + "service-account"
+
+	// PruneProtectAnnotation, when set to "true" on a resource managed by
+	// kro, protects it from deletion: it is left in place when its instance
+	// is deleted.
+	PruneProtectAnnotation = AnnotationKROPrefix + "prune-protect"
+
+	// ResourceIDAnnotation is set by kro on the resources it manages, to the
+	// id of the resource graph definition resource they were created from.
+	ResourceIDAnnotation = AnnotationKROPrefix + "resource-id"
+
+	// ReconcileIntervalAnnotation sets the period at which an instance is
+	// requeued while its resources are in progress (e.g "30s"), overriding
+	// the controller default. It is useful for resources whose readiness
+	// has to be polled.
+	ReconcileIntervalAnnotation = AnnotationKROPrefix + "reconcile-interval"
+
+	// TTLAnnotation sets when an instance expires, after which kro deletes
+	// it. The value is either a duration counted from the instance creation
+	// (e.g "24h"), or an RFC 3339 timestamp (e.g "2025-01-01T00:00:00Z").
+	TTLAnnotation = AnnotationKROPrefix + "ttl"
+
+	// StoreManifestsAnnotation, when set to "true" on an instance, makes kro
+	// store the manifests it resolved for the instance resources in a
+	// ConfigMap next to the instance, for audit and debugging purposes.
+	StoreManifestsAnnotation = AnnotationKROPrefix + "store-manifests"
+)
+
+// ReconcileRequested returns true if the value of the ReconcileAnnotation
+// differs between the old and the new object.
+func ReconcileRequested(oldObj, newObj metav1.Object) bool {
+	return oldObj.GetAnnotations()[ReconcileAnnotation] != newObj.GetAnnotations()[ReconcileAnnotation]
+}
+
+// KROAnnotationsChanged returns true if any of the kro.run/ annotations was
+// added, removed or changed between the old and the new object.
+func KROAnnotationsChanged(oldObj, newObj metav1.Object) bool {
+	return !maps.Equal(kroAnnotations(oldObj), kroAnnotations(newObj))
+}
+
+// kroAnnotations returns the kro.run/ annotations of the object.
+func kroAnnotations(obj metav1.Object) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range obj.GetAnnotations() {
+		if strings.HasPrefix(key, AnnotationKROPrefix) {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// GetApplyOnlyResources returns the set of resource IDs listed in the
+// ApplyOnlyAnnotation, or nil if the annotation is absent or empty.
+func GetApplyOnlyResources(obj metav1.Object) map[string]struct{} {
+	value, ok := obj.GetAnnotations()[ApplyOnlyAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var ids map[string]struct{}
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if ids == nil {
+			ids = make(map[string]struct{})
+		}
+		ids[id] = struct{}{}
+	}
+	return ids
+}
+
+// GetReconcileInterval returns the duration set by the
+// ReconcileIntervalAnnotation, or zero if the annotation is absent.
+func GetReconcileInterval(obj metav1.Object) (time.Duration, error) {
+	value, ok := obj.GetAnnotations()[ReconcileIntervalAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation: %w", ReconcileIntervalAnnotation, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation: %s is not a positive duration", ReconcileIntervalAnnotation, value)
+	}
+	return interval, nil
+}
+
+// GetExpiry returns the time at which the object expires according to its
+// TTLAnnotation. The boolean is false if the annotation is absent.
+func GetExpiry(obj metav1.Object) (time.Time, bool, error) {
+	value, ok := obj.GetAnnotations()[TTLAnnotation]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if ttl, err := time.ParseDuration(value); err == nil {
+		if ttl <= 0 {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation: %s is not a positive duration", TTLAnnotation, value)
+		}
+		return obj.GetCreationTimestamp().Add(ttl), true, nil
+	}
+	expiry, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s annotation: %s is neither a duration nor an RFC 3339 timestamp", TTLAnnotation, value)
+	}
+	return expiry, true, nil
+}
+
+// IsSuspended returns true if the SuspendAnnotation of the object is set to
+// "true".
+func IsSuspended(obj metav1.Object) bool {
+	return obj.GetAnnotations()[SuspendAnnotation] == "true"
+}
+
+// GetServiceAccount returns the service account named by the
+// ServiceAccountAnnotation of the object, or an empty string if there is
+// none.
+func GetServiceAccount(obj metav1.Object) string {
+	return strings.TrimSpace(obj.GetAnnotations()[ServiceAccountAnnotation])
+}
+
+// IsStoringManifests returns true if the StoreManifestsAnnotation of the
+// object is set to "true".
+func IsStoringManifests(obj metav1.Object) bool {
+	return obj.GetAnnotations()[StoreManifestsAnnotation] == "true"
+}
+
+// IsPruneProtected returns true if the PruneProtectAnnotation of the object is
+// set to "true".
+func IsPruneProtected(obj metav1.Object) bool {
+	return obj.GetAnnotations()[PruneProtectAnnotation] == "true"
+}
+
+// GetPropagatedAnnotations returns the instance annotations whose key is in
+// keys. Keys the instance doesn't have are ignored.
+func GetPropagatedAnnotations(instanceMeta metav1.Object, keys []string) map[string]string {
+	return selectKeys(instanceMeta.GetAnnotations(), keys)
+}
+
+// SetAnnotations sets the given annotations on the object, overriding the
+// existing values.
+func SetAnnotations(obj metav1.Object, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	existing := obj.GetAnnotations()
+	if existing == nil {
+		existing = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		existing[k] = v
+	}
+	obj.SetAnnotations(existing)
+}
+
+// SetMissingAnnotations sets the given annotations the object doesn't already
+// have, keeping the existing values.
+func SetMissingAnnotations(obj metav1.Object, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	existing := obj.GetAnnotations()
+	if existing == nil {
+		existing = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		if _, ok := existing[k]; !ok {
+			existing[k] = v
+		}
+	}
+	obj.SetAnnotations(existing)
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileRequested(t *testing.T) {
+	tests := []struct {
+		name string
+		old  map[string]string
+		new  map[string]string
+		want bool
+	}{
+		{"no annotations", nil, nil, false},
+		{"annotation added", nil, map[string]string{ReconcileAnnotation: "1"}, true},
+		{"annotation changed", map[string]string{ReconcileAnnotation: "1"}, map[string]string{ReconcileAnnotation: "2"}, true},
+		{"annotation unchanged", map[string]string{ReconcileAnnotation: "1"}, map[string]string{ReconcileAnnotation: "1"}, false},
+		{"annotation removed", map[string]string{ReconcileAnnotation: "1"}, nil, true},
+		{"other annotation changed", map[string]string{"foo": "1"}, map[string]string{"foo": "2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldObj := &metav1.ObjectMeta{Annotations: tt.old}
+			newObj := &metav1.ObjectMeta{Annotations: tt.new}
+			assert.Equal(t, tt.want, ReconcileRequested(oldObj, newObj))
+		})
+	}
+}
+
+func TestKROAnnotationsChanged(t *testing.T) {
+	tests := []struct {
+		name string
+		old  map[string]string
+		new  map[string]string
+		want bool
+	}{
+		{"no annotations", nil, nil, false},
+		{"annotation added", nil, map[string]string{SuspendAnnotation: "true"}, true},
+		{"annotation changed", map[string]string{TTLAnnotation: "1h"}, map[string]string{TTLAnnotation: "2h"}, true},
+		{"annotation removed", map[string]string{SuspendAnnotation: "true"}, nil, true},
+		{"other annotation changed", map[string]string{"foo": "1"}, map[string]string{"foo": "2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldObj := &metav1.ObjectMeta{Annotations: tt.old}
+			newObj := &metav1.ObjectMeta{Annotations: tt.new}
+			assert.Equal(t, tt.want, KROAnnotationsChanged(oldObj, newObj))
+		})
+	}
+}
+
+func TestGetApplyOnlyResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]struct{}
+	}{
+		{"no annotation", nil, nil},
+		{"empty annotation", map[string]string{ApplyOnlyAnnotation: ""}, nil},
+		{"single id", map[string]string{ApplyOnlyAnnotation: "deployment"}, map[string]struct{}{"deployment": {}}},
+		{
+			"multiple ids with spaces",
+			map[string]string{ApplyOnlyAnnotation: "deployment, service ,,"},
+			map[string]struct{}{"deployment": {}, "service": {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tt.annotations}
+			assert.Equal(t, tt.want, GetApplyOnlyResources(obj))
+		})
+	}
+}
+
+func TestGetReconcileInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{"no annotation", nil, 0, false},
+		{"valid duration", map[string]string{ReconcileIntervalAnnotation: "45s"}, 45 * time.Second, false},
+		{"invalid duration", map[string]string{ReconcileIntervalAnnotation: "often"}, 0, true},
+		{"negative duration", map[string]string{ReconcileIntervalAnnotation: "-1m"}, 0, true},
+		{"zero duration", map[string]string{ReconcileIntervalAnnotation: "0s"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetReconcileInterval(&metav1.ObjectMeta{Annotations: tt.annotations})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetExpiry(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Time
+		wantFound   bool
+		wantErr     bool
+	}{
+		{"no annotation", nil, time.Time{}, false, false},
+		{"duration", map[string]string{TTLAnnotation: "24h"}, created.Add(24 * time.Hour), true, false},
+		{"timestamp", map[string]string{TTLAnnotation: "2025-02-01T12:00:00Z"}, time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC), true, false},
+		{"negative duration", map[string]string{TTLAnnotation: "-1h"}, time.Time{}, false, true},
+		{"invalid value", map[string]string{TTLAnnotation: "tomorrow"}, time.Time{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := GetExpiry(&metav1.ObjectMeta{
+				Annotations:       tt.annotations,
+				CreationTimestamp: metav1.NewTime(created),
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFound, found)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestIsSuspended(t *testing.T) {
+	assert.False(t, IsSuspended(&metav1.ObjectMeta{}))
+	assert.False(t, IsSuspended(&metav1.ObjectMeta{Annotations: map[string]string{SuspendAnnotation: "false"}}))
+	assert.True(t, IsSuspended(&metav1.ObjectMeta{Annotations: map[string]string{SuspendAnnotation: "true"}}))
+}
+
+func TestGetServiceAccount(t *testing.T) {
+	assert.Empty(t, GetServiceAccount(&metav1.ObjectMeta{}))
+	assert.Equal(t, "deployer", GetServiceAccount(&metav1.ObjectMeta{Annotations: map[string]string{ServiceAccountAnnotation: " deployer "}}))
+}
+
+func TestIsStoringManifests(t *testing.T) {
+	assert.False(t, IsStoringManifests(&metav1.ObjectMeta{}))
+	assert.False(t, IsStoringManifests(&metav1.ObjectMeta{Annotations: map[string]string{StoreManifestsAnnotation: "false"}}))
+	assert.True(t, IsStoringManifests(&metav1.ObjectMeta{Annotations: map[string]string{StoreManifestsAnnotation: "true"}}))
+}
+
+func TestIsPruneProtected(t *testing.T) {
+	assert.False(t, IsPruneProtected(&metav1.ObjectMeta{}))
+	assert.False(t, IsPruneProtected(&metav1.ObjectMeta{Annotations: map[string]string{PruneProtectAnnotation: "yes"}}))
+	assert.True(t, IsPruneProtected(&metav1.ObjectMeta{Annotations: map[string]string{PruneProtectAnnotation: "true"}}))
+}
+
+func TestGetPropagatedAnnotations(t *testing.T) {
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{
+		"cost-center": "1234",
+		"owner":       "someone",
+	}}
+
+	assert.Equal(t, map[string]string{"cost-center": "1234"}, GetPropagatedAnnotations(obj, []string{"cost-center", "missing"}))
+	assert.Empty(t, GetPropagatedAnnotations(obj, nil))
+}
+
+func TestSetAnnotations(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	SetAnnotations(obj, nil)
+	assert.Nil(t, obj.Annotations)
+
+	obj.Annotations = map[string]string{"a": "1", "b": "2"}
+	SetAnnotations(obj, map[string]string{"b": "3", "c": "4"})
+	assert.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, obj.Annotations)
+}
+
+func TestSetMissingAnnotations(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	SetMissingAnnotations(obj, nil)
+	assert.Nil(t, obj.Annotations)
+
+	obj.Annotations = map[string]string{"a": "1", "b": "2"}
+	SetMissingAnnotations(obj, map[string]string{"b": "3", "c": "4"})
+	assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "4"}, obj.Annotations)
+}
@@ -24,45 +24,48 @@ import (
 	"github.com/kro-run/kro/api/v1alpha1"
 )
 
-const kroFinalizer = v1alpha1.KRODomainName + "/finalizer"
+// DefaultFinalizer is the finalizer kro sets on the resource graph definitions,
+// and on the instances unless the controller is configured with another one.
+const DefaultFinalizer = v1alpha1.KRODomainName + "/finalizer"
 
 // SetResourceGraphDefinitionFinalizer adds the kro finalizer to the object if it's not already present.
 func SetResourceGraphDefinitionFinalizer(obj metav1.Object) {
 	if !HasResourceGraphDefinitionFinalizer(obj) {
-		obj.SetFinalizers(append(obj.GetFinalizers(), kroFinalizer))
+		obj.SetFinalizers(append(obj.GetFinalizers(), DefaultFinalizer))
 	}
 }
 
 // RemoveResourceGraphDefinitionFinalizer removes the kro finalizer from the object.
 func RemoveResourceGraphDefinitionFinalizer(obj metav1.Object) {
-	obj.SetFinalizers(removeString(obj.GetFinalizers(), kroFinalizer))
+	obj.SetFinalizers(removeString(obj.GetFinalizers(), DefaultFinalizer))
 }
 
 // HasResourceGraphDefinitionFinalizer checks if the object has the kro finalizer.
 func HasResourceGraphDefinitionFinalizer(obj metav1.Object) bool {
-	return containsString(obj.GetFinalizers(), kroFinalizer)
+	return containsString(obj.GetFinalizers(), DefaultFinalizer)
 }
 
-// SetInstanceFinalizerUnstructured adds an instance-specific finalizer to an unstructured object.
-func SetInstanceFinalizerUnstructured(obj *unstructured.Unstructured) error {
+// SetInstanceFinalizerUnstructured adds the instance finalizer to an unstructured object.
+func SetInstanceFinalizerUnstructured(obj *unstructured.Unstructured, finalizer string) error {
 	finalizers := obj.GetFinalizers()
-	if !containsString(finalizers, kroFinalizer) {
-		finalizers = append(finalizers, kroFinalizer)
+	if !containsString(finalizers, finalizer) {
+		finalizers = append(finalizers, finalizer)
 		obj.SetFinalizers(finalizers)
 	}
 	return nil
 }
 
-// RemoveInstanceFinalizerUnstructured removes an instance-specific finalizer from an unstructured object.
-func RemoveInstanceFinalizerUnstructured(obj *unstructured.Unstructured) error {
-	if controllerutil.ContainsFinalizer(obj, kroFinalizer) {
-		controllerutil.RemoveFinalizer(obj, kroFinalizer)
+// RemoveInstanceFinalizerUnstructured removes the instance finalizer from an unstructured object.
+// The finalizers of other controllers, including other kro deployments, are left untouched.
+func RemoveInstanceFinalizerUnstructured(obj *unstructured.Unstructured, finalizer string) error {
+	if controllerutil.ContainsFinalizer(obj, finalizer) {
+		controllerutil.RemoveFinalizer(obj, finalizer)
 	}
 	return nil
 }
 
-// HasInstanceFinalizerUnstructured checks if an unstructured object has an instance-specific finalizer.
-func HasInstanceFinalizerUnstructured(obj *unstructured.Unstructured) (bool, error) {
+// HasInstanceFinalizerUnstructured checks if an unstructured object has the instance finalizer.
+func HasInstanceFinalizerUnstructured(obj *unstructured.Unstructured, finalizer string) (bool, error) {
 	finalizers, found, err := unstructured.NestedStringSlice(obj.Object, "metadata", "finalizers")
 	if err != nil {
 		return false, fmt.Errorf("error getting finalizers: %w", err)
@@ -72,7 +75,7 @@ func HasInstanceFinalizerUnstructured(obj *unstructured.Unstructured) (bool, err
 		return false, nil
 	}
 
-	return containsString(finalizers, kroFinalizer), nil
+	return containsString(finalizers, finalizer), nil
 }
 
 // Helper functions
@@ -46,7 +46,7 @@ func TestResourceGraphDefinitionFinalizer(t *testing.T) {
 		},
 		{
 			name:          "Remove finalizer from object w/ finalizer",
-			initialObject: &metav1.ObjectMeta{Finalizers: []string{kroFinalizer}},
+			initialObject: &metav1.ObjectMeta{Finalizers: []string{DefaultFinalizer}},
 			operation:     RemoveResourceGraphDefinitionFinalizer,
 			check:         HasResourceGraphDefinitionFinalizer,
 			expected:      false,
@@ -72,8 +72,9 @@ func TestInstanceFinalizerUnstructured(t *testing.T) {
 	cases := []struct {
 		name          string
 		initialObject *unstructured.Unstructured
-		operation     func(*unstructured.Unstructured) error
-		check         func(*unstructured.Unstructured) (bool, error)
+		finalizer     string
+		operation     func(*unstructured.Unstructured, string) error
+		check         func(*unstructured.Unstructured, string) (bool, error)
 		expected      bool
 		expectError   bool
 	}{
@@ -106,7 +107,7 @@ func TestInstanceFinalizerUnstructured(t *testing.T) {
 			initialObject: &unstructured.Unstructured{
 				Object: map[string]interface{}{
 					"metadata": map[string]interface{}{
-						"finalizers": []interface{}{kroFinalizer},
+						"finalizers": []interface{}{DefaultFinalizer},
 					},
 				},
 			},
@@ -127,16 +128,50 @@ func TestInstanceFinalizerUnstructured(t *testing.T) {
 			check:     HasInstanceFinalizerUnstructured,
 			expected:  false,
 		},
+		{
+			name: "Remove a configured instance finalizer",
+			initialObject: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"finalizers": []interface{}{"team-a.kro.run/finalizer"},
+					},
+				},
+			},
+			finalizer: "team-a.kro.run/finalizer",
+			operation: RemoveInstanceFinalizerUnstructured,
+			check:     HasInstanceFinalizerUnstructured,
+			expected:  false,
+		},
+		{
+			name: "Remove a configured instance finalizer leaves the other kro finalizers",
+			initialObject: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"finalizers": []interface{}{DefaultFinalizer},
+					},
+				},
+			},
+			finalizer: "team-a.kro.run/finalizer",
+			operation: RemoveInstanceFinalizerUnstructured,
+			check: func(obj *unstructured.Unstructured, _ string) (bool, error) {
+				return HasInstanceFinalizerUnstructured(obj, DefaultFinalizer)
+			},
+			expected: true,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := tc.operation(tc.initialObject)
+			finalizer := tc.finalizer
+			if finalizer == "" {
+				finalizer = DefaultFinalizer
+			}
+			err := tc.operation(tc.initialObject, finalizer)
 			if tc.expectError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				hasF, err := tc.check(tc.initialObject)
+				hasF, err := tc.check(tc.initialObject, finalizer)
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expected, hasF)
 			}
@@ -47,6 +47,27 @@ const (
 	ResourceGraphDefinitionVersionLabel   = LabelKROPrefix + "resource-graph-definition-version"
 )
 
+// reservedLabels are the labels set by kro on the resources it manages.
+var reservedLabels = map[string]struct{}{
+	NodeIDLabel:                           {},
+	OwnedLabel:                            {},
+	KROVersionLabel:                       {},
+	InstanceIDLabel:                       {},
+	InstanceLabel:                         {},
+	InstanceNamespaceLabel:                {},
+	ResourceGraphDefinitionIDLabel:        {},
+	ResourceGraphDefinitionNameLabel:      {},
+	ResourceGraphDefinitionNamespaceLabel: {},
+	ResourceGraphDefinitionVersionLabel:   {},
+}
+
+// IsReservedLabel returns true if the label is set by kro on the resources it
+// manages, and can't be set by the resource templates.
+func IsReservedLabel(key string) bool {
+	_, ok := reservedLabels[key]
+	return ok
+}
+
 // IsKROOwned returns true if the resource is owned by KRO.
 func IsKROOwned(meta metav1.ObjectMeta) bool {
 	v, ok := meta.Labels[OwnedLabel]
@@ -135,6 +156,13 @@ func NewInstanceLabeler(instanceMeta metav1.Object) GenericLabeler {
 	}
 }
 
+// NewPropagatedLabeler returns a new labeler that sets the given label keys
+// to the values they have on the instance. Keys the instance doesn't have
+// are ignored.
+func NewPropagatedLabeler(instanceMeta metav1.Object, keys []string) GenericLabeler {
+	return selectKeys(instanceMeta.GetLabels(), keys)
+}
+
 // NewKROMetaLabeler returns a new labeler that sets the OwnedLabel,
 // KROVersion, and ControllerPodID labels on a resource.
 func NewKROMetaLabeler() GenericLabeler {
@@ -153,6 +181,28 @@ func safeVersion(version string) string {
 	return strings.ReplaceAll(version, "+", "-")
 }
 
+// SetMissingLabels sets the given labels the object doesn't already have,
+// keeping the existing values.
+func SetMissingLabels(meta metav1.Object, labels map[string]string) {
+	existing := meta.GetLabels()
+	for k, v := range labels {
+		if _, ok := existing[k]; !ok {
+			setLabel(meta, k, v)
+		}
+	}
+}
+
+// selectKeys returns the entries of m whose key is in keys.
+func selectKeys(m map[string]string, keys []string) map[string]string {
+	selected := map[string]string{}
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			selected[k] = v
+		}
+	}
+	return selected
+}
+
 func booleanFromString(s string) bool {
 	// for the sake of simplicity we'll avoid doing any kind
 	// of parsing here. Since those labels are set by the controller
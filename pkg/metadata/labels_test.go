@@ -216,6 +216,23 @@ func TestNewInstanceLabeler(t *testing.T) {
 	})
 }
 
+func TestNewPropagatedLabeler(t *testing.T) {
+	t.Run("NewPropagatedLabeler", func(t *testing.T) {
+		obj := &mockObject{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			"team":        "platform",
+			"environment": "prod",
+		}}}
+		labeler := NewPropagatedLabeler(obj, []string{"team", "missing"})
+		assert.Equal(t, GenericLabeler{"team": "platform"}, labeler)
+	})
+}
+
+func TestSetMissingLabels(t *testing.T) {
+	obj := &mockObject{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "platform"}}}
+	SetMissingLabels(obj, map[string]string{"team": "infra", "environment": "prod"})
+	assert.Equal(t, map[string]string{"team": "platform", "environment": "prod"}, obj.Labels)
+}
+
 func TestNewKROMetaLabeler(t *testing.T) {
 	t.Run("NewKROMetaLabeler", func(t *testing.T) {
 		labeler := NewKROMetaLabeler()
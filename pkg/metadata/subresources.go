@@ -0,0 +1,79 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+// SubResourceMetadata is the metadata kro sets on the resources of an
+// instance: the labels kro relies on, the instance labels and annotations
+// propagated to the resources, and the id of the resource in the graph.
+type SubResourceMetadata struct {
+	// Labeler applies the labels kro relies on, they always win over the
+	// template and the propagated labels.
+	Labeler Labeler
+	// PropagatedLabels are the instance labels to copy onto the resources.
+	PropagatedLabels map[string]string
+	// PropagatedAnnotations are the instance annotations to copy onto the
+	// resources.
+	PropagatedAnnotations map[string]string
+	// TemplatePrecedence keeps the labels and annotations set by the resource
+	// templates over the propagated ones.
+	TemplatePrecedence bool
+}
+
+// NewSubResourceMetadata returns the metadata to set on the resources of the
+// instance. labeler applies the labels kro relies on, and propagation, which
+// can be nil, selects the instance labels and annotations to propagate.
+func NewSubResourceMetadata(
+	instance metav1.Object,
+	labeler Labeler,
+	propagation *v1alpha1.Propagation,
+) (SubResourceMetadata, error) {
+	m := SubResourceMetadata{Labeler: labeler}
+	if propagation == nil {
+		return m, nil
+	}
+
+	m.PropagatedLabels = NewPropagatedLabeler(instance, propagation.Labels)
+	// The propagated labels can't override the labels kro relies on.
+	if _, err := labeler.Merge(GenericLabeler(m.PropagatedLabels)); err != nil {
+		return m, fmt.Errorf("failed to propagate instance labels: %w", err)
+	}
+	m.PropagatedAnnotations = GetPropagatedAnnotations(instance, propagation.Annotations)
+	m.TemplatePrecedence = propagation.Precedence == v1alpha1.PropagationPrecedenceTemplate
+	return m, nil
+}
+
+// Apply sets the metadata on the resource with the given id in the graph.
+func (m SubResourceMetadata) Apply(resourceID string, obj metav1.Object) {
+	if m.TemplatePrecedence {
+		SetMissingLabels(obj, m.PropagatedLabels)
+		SetMissingAnnotations(obj, m.PropagatedAnnotations)
+	} else {
+		GenericLabeler(m.PropagatedLabels).ApplyLabels(obj)
+		SetAnnotations(obj, m.PropagatedAnnotations)
+	}
+	// The labels and annotations kro relies on always win.
+	if m.Labeler != nil {
+		m.Labeler.ApplyLabels(obj)
+	}
+	SetAnnotations(obj, map[string]string{ResourceIDAnnotation: resourceID})
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+func TestSubResourceMetadata(t *testing.T) {
+	instance := &metav1.ObjectMeta{
+		Labels:      map[string]string{"team": "instance"},
+		Annotations: map[string]string{"owner": "instance"},
+	}
+	labeler := GenericLabeler{InstanceIDLabel: "uid"}
+
+	t.Run("without propagation", func(t *testing.T) {
+		m, err := NewSubResourceMetadata(instance, labeler, nil)
+		require.NoError(t, err)
+
+		obj := &metav1.ObjectMeta{}
+		m.Apply("configmap", obj)
+		assert.Equal(t, map[string]string{InstanceIDLabel: "uid"}, obj.Labels)
+		assert.Equal(t, map[string]string{ResourceIDAnnotation: "configmap"}, obj.Annotations)
+	})
+
+	t.Run("instance precedence", func(t *testing.T) {
+		m, err := NewSubResourceMetadata(instance, labeler, &v1alpha1.Propagation{
+			Labels:      []string{"team"},
+			Annotations: []string{"owner"},
+		})
+		require.NoError(t, err)
+
+		obj := &metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "template", InstanceIDLabel: "template"},
+			Annotations: map[string]string{"owner": "template"},
+		}
+		m.Apply("configmap", obj)
+		assert.Equal(t, map[string]string{"team": "instance", InstanceIDLabel: "uid"}, obj.Labels)
+		assert.Equal(t, map[string]string{"owner": "instance", ResourceIDAnnotation: "configmap"}, obj.Annotations)
+	})
+
+	t.Run("template precedence", func(t *testing.T) {
+		m, err := NewSubResourceMetadata(instance, labeler, &v1alpha1.Propagation{
+			Labels:      []string{"team"},
+			Annotations: []string{"owner"},
+			Precedence:  v1alpha1.PropagationPrecedenceTemplate,
+		})
+		require.NoError(t, err)
+
+		obj := &metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "template", InstanceIDLabel: "template"},
+			Annotations: map[string]string{"owner": "template"},
+		}
+		m.Apply("configmap", obj)
+		assert.Equal(t, map[string]string{"team": "template", InstanceIDLabel: "uid"}, obj.Labels)
+		assert.Equal(t, map[string]string{"owner": "template", ResourceIDAnnotation: "configmap"}, obj.Annotations)
+	})
+
+	t.Run("propagated labels can't override kro labels", func(t *testing.T) {
+		_, err := NewSubResourceMetadata(
+			&metav1.ObjectMeta{Labels: map[string]string{InstanceIDLabel: "other"}},
+			labeler,
+			&v1alpha1.Propagation{Labels: []string{InstanceIDLabel}},
+		)
+		assert.Error(t, err)
+	})
+}
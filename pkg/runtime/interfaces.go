@@ -15,9 +15,12 @@
 package runtime
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
+	"github.com/kro-run/kro/api/v1alpha1"
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
 
@@ -55,6 +58,11 @@ type Interface interface {
 	// GetInstance returns the main instance object managed by this runtime.
 	GetInstance() *unstructured.Unstructured
 
+	// GetInstanceStatus returns the status fields of the instance computed
+	// from the status expressions of the resource graph definition. The fields
+	// whose expressions aren't resolved yet are omitted.
+	GetInstanceStatus() map[string]interface{}
+
 	// SetInstance updates the main instance object.
 	// This is typically called after the instance has been updated in the cluster.
 	SetInstance(obj *unstructured.Unstructured)
@@ -62,6 +70,11 @@ type Interface interface {
 	// IsResourceReady returns true if the resource is ready, and false otherwise.
 	IsResourceReady(resourceID string) (bool, string, error)
 
+	// IsInstanceReady returns true if the ready expression of the instance
+	// evaluates to true, or if there is no ready expression. Otherwise, it
+	// returns false along with the reason.
+	IsInstanceReady() (bool, string, error)
+
 	// ReadyToProcessResource returns true if all the condition expressions return true
 	// if not it will add itself to the ignored resources
 	ReadyToProcessResource(resourceID string) (bool, error)
@@ -106,6 +119,10 @@ type ResourceDescriptor interface {
 	// evaluated before the resource is considered ready.
 	GetReadyWhenExpressions() []string
 
+	// GetReadyWhenJSONPaths returns the list of JSONPath checks that need to
+	// match the observed resource before it is considered ready.
+	GetReadyWhenJSONPaths() []v1alpha1.ReadyWhenJSONPath
+
 	// GetIncludeWhenExpressions returns the list of expressions that need to
 	// be evaluated before deciding whether to create a resource
 	GetIncludeWhenExpressions() []string
@@ -117,6 +134,21 @@ type ResourceDescriptor interface {
 	// IsExternalRef returns true if the resource is marked as an external reference
 	// This is used for external references
 	IsExternalRef() bool
+
+	// DeleteWithInstance returns true if the external reference should be
+	// deleted when the instance is deleted.
+	DeleteWithInstance() bool
+
+	// ReconcileOnce returns true if the resource should only be created, and
+	// never updated once it exists.
+	ReconcileOnce() bool
+
+	// DeletionPropagation returns the propagation policy used to delete the
+	// resource, or an empty policy to use the controller default.
+	DeletionPropagation() metav1.DeletionPropagation
+
+	// GetSchema returns the OpenAPI schema of the resource.
+	GetSchema() *spec.Schema
 }
 
 // Resource extends `ResourceDescriptor` to include the actual resource data.
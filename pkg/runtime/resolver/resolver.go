@@ -85,6 +85,12 @@ func (r *Resolver) UpsertValueAtPath(path string, value interface{}) error {
 	return r.setValueAtPath(path, value)
 }
 
+// ValueAtPath returns the value of the resource at the given path, or an
+// error if there is none.
+func (r *Resolver) ValueAtPath(path string) (interface{}, error) {
+	return r.getValueFromPath(path)
+}
+
 // resolveField handles the resolution of a single ExpressionField (one field) in
 // the resource. It returns a ResolutionResult containing information about the
 // resolution process
@@ -94,7 +100,15 @@ func (r *Resolver) resolveField(field variable.FieldDescriptor) ResolutionResult
 		Original: fmt.Sprintf("%v", field.Expressions),
 	}
 
-	value, err := r.getValueFromPath(field.Path)
+	var value interface{}
+	var err error
+	if field.StandaloneExpression {
+		// The field may have been omitted by a previous resolution (see
+		// below), only its parent has to exist.
+		_, err = r.getParentValueFromPath(field.Path)
+	} else {
+		value, err = r.getValueFromPath(field.Path)
+	}
 	if err != nil {
 		// Not sure if these kinds of errors should be fatal, these paths are produced
 		// by the parser, so they should be valid.
@@ -109,6 +123,20 @@ func (r *Resolver) resolveField(field variable.FieldDescriptor) ResolutionResult
 			result.Error = fmt.Errorf("no data provided for expression: %s", field.Expressions[0])
 			return result
 		}
+		if resolvedValue == nil {
+			// A map entry resolving to null is omitted entirely, rather than
+			// being set to null. This allows to conditionally set a field, e.g
+			// key: ${schema.spec.enabled ? optional.of("value") : optional.none()}
+			omitted, err := r.deleteMapEntryAtPath(field.Path)
+			if err != nil {
+				result.Error = fmt.Errorf("error omitting value: %v", err)
+				return result
+			}
+			if omitted {
+				result.Resolved = true
+				return result
+			}
+		}
 		err = r.setValueAtPath(field.Path, resolvedValue)
 		if err != nil {
 			result.Error = fmt.Errorf("error setting value: %v", err)
@@ -156,7 +184,26 @@ func (r *Resolver) getValueFromPath(path string) (interface{}, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid path '%s': %v", path, err)
 	}
+	return r.getValueFromSegments(segments)
+}
 
+// getParentValueFromPath retrieves the value holding the last segment of the
+// path.
+func (r *Resolver) getParentValueFromPath(path string) (interface{}, error) {
+	path = strings.TrimPrefix(path, ".") // Remove leading dot if present
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path '%s': %v", path, err)
+	}
+	if len(segments) == 0 {
+		return r.resource, nil
+	}
+	return r.getValueFromSegments(segments[:len(segments)-1])
+}
+
+// getValueFromSegments retrieves a value from the resource using parsed path
+// segments.
+func (r *Resolver) getValueFromSegments(segments []fieldpath.Segment) (interface{}, error) {
 	current := interface{}(r.resource)
 
 	for _, segment := range segments {
@@ -190,6 +237,30 @@ func (r *Resolver) getValueFromPath(path string) (interface{}, error) {
 	return current, nil
 }
 
+// deleteMapEntryAtPath removes the map entry at the given path. It returns
+// false, without modifying the resource, if the path doesn't point to a map
+// entry (e.g an array element).
+func (r *Resolver) deleteMapEntryAtPath(path string) (bool, error) {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return false, fmt.Errorf("invalid path '%s': %v", path, err)
+	}
+	if len(segments) == 0 || segments[len(segments)-1].Index >= 0 {
+		return false, nil
+	}
+
+	parent, err := r.getValueFromSegments(segments[:len(segments)-1])
+	if err != nil {
+		return false, err
+	}
+	parentMap, ok := parent.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("expected map at path %s", path)
+	}
+	delete(parentMap, segments[len(segments)-1].Name)
+	return true, nil
+}
+
 // setValueAtPath sets a value in the resource using a dot-separated path.
 func (r *Resolver) setValueAtPath(path string, value interface{}) error {
 	segments, err := fieldpath.Parse(path)
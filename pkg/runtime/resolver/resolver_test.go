@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
@@ -605,6 +606,99 @@ func TestResolveField(t *testing.T) {
 	}
 }
 
+func TestResolveFieldOmitsNullMapEntries(t *testing.T) {
+	newResource := func() map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{
+					"autoscaling": "${autoscaling}",
+					"team":        "platform",
+				},
+			},
+			"spec": map[string]interface{}{
+				"args": []interface{}{"${autoscaling}"},
+			},
+		}
+	}
+	annotationField := variable.FieldDescriptor{
+		Path:                 "metadata.annotations.autoscaling",
+		Expressions:          []string{"autoscaling"},
+		StandaloneExpression: true,
+	}
+
+	t.Run("null value omits the map entry", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"autoscaling": nil})
+
+		got := r.resolveField(annotationField)
+		require.NoError(t, got.Error)
+		assert.True(t, got.Resolved)
+		assert.Equal(t, map[string]interface{}{"team": "platform"},
+			resource["metadata"].(map[string]interface{})["annotations"])
+	})
+
+	t.Run("omitted map entry can be resolved again", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"autoscaling": nil})
+		require.NoError(t, r.resolveField(annotationField).Error)
+
+		got := r.resolveField(annotationField)
+		require.NoError(t, got.Error)
+		assert.True(t, got.Resolved)
+		assert.Equal(t, map[string]interface{}{"team": "platform"},
+			resource["metadata"].(map[string]interface{})["annotations"])
+	})
+
+	t.Run("non null value sets the map entry", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"autoscaling": "enabled"})
+
+		got := r.resolveField(annotationField)
+		require.NoError(t, got.Error)
+		assert.True(t, got.Resolved)
+		assert.Equal(t, map[string]interface{}{"autoscaling": "enabled", "team": "platform"},
+			resource["metadata"].(map[string]interface{})["annotations"])
+	})
+
+	t.Run("null value in an array is kept", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"autoscaling": nil})
+
+		got := r.resolveField(variable.FieldDescriptor{
+			Path:                 "spec.args[0]",
+			Expressions:          []string{"autoscaling"},
+			StandaloneExpression: true,
+		})
+		require.NoError(t, got.Error)
+		assert.True(t, got.Resolved)
+		assert.Equal(t, []interface{}{nil}, resource["spec"].(map[string]interface{})["args"])
+	})
+
+	t.Run("missing optional field of an external reference omits the map entry", func(t *testing.T) {
+		// ${external.data.?VALUE} evaluates to null when the referenced
+		// ConfigMap has no VALUE key: the env var is left without a value,
+		// rather than with a null one.
+		resource := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"env": []interface{}{
+					map[string]interface{}{"name": "VALUE", "value": "${external.data.?VALUE}"},
+				},
+			},
+		}
+		r := NewResolver(resource, map[string]interface{}{"external.data.?VALUE": nil})
+
+		got := r.resolveField(variable.FieldDescriptor{
+			Path:                 "spec.env[0].value",
+			Expressions:          []string{"external.data.?VALUE"},
+			StandaloneExpression: true,
+		})
+		require.NoError(t, got.Error)
+		assert.True(t, got.Resolved)
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "VALUE"}},
+			resource["spec"].(map[string]interface{})["env"])
+	})
+}
+
 func TestResolveDynamicArrayIndexes(t *testing.T) {
 	resource := map[string]interface{}{
 		"spec": map[string]interface{}{
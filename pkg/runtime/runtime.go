@@ -15,13 +15,17 @@
 package runtime
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
 
 	"github.com/google/cel-go/cel"
 	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/api/validation/path"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
 
 	krocel "github.com/kro-run/kro/pkg/cel"
 	"github.com/kro-run/kro/pkg/graph/variable"
@@ -46,6 +50,7 @@ func NewResourceGraphDefinitionRuntime(
 	instance Resource,
 	resources map[string]Resource,
 	topologicalOrder []string,
+	opts ...Option,
 ) (*ResourceGraphDefinitionRuntime, error) {
 	r := &ResourceGraphDefinitionRuntime{
 		instance:                     instance,
@@ -56,6 +61,16 @@ func NewResourceGraphDefinitionRuntime(
 		expressionsCache:             make(map[string]*expressionEvaluationState),
 		ignoredByConditionsResources: make(map[string]bool),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.schemaDefaulter != nil {
+		// Surface the computed defaults errors early, later evaluations
+		// can then safely ignore them.
+		if err := r.schemaDefaulter(instance.Unstructured().DeepCopy()); err != nil {
+			return nil, fmt.Errorf("failed to compute instance defaults: %w", err)
+		}
+	}
 	// make sure to copy the variables and the dependencies, to avoid
 	// modifying the original resource.
 	for id, resource := range resources {
@@ -170,6 +185,50 @@ type ResourceGraphDefinitionRuntime struct {
 	// ignoredByConditionsResources holds the resources who's defined conditions returned false
 	// or who's dependencies are ignored
 	ignoredByConditionsResources map[string]bool
+
+	// schemaDefaulter, when set, fills in the computed defaults of the
+	// instance before it is exposed to expressions as "schema".
+	schemaDefaulter func(*unstructured.Unstructured) error
+
+	// readyExpression, when set, decides whether the instance is ready. It
+	// can only be evaluated once all of readyExpressionDependencies are
+	// resolved.
+	readyExpression             string
+	readyExpressionDependencies []string
+}
+
+// Option configures a ResourceGraphDefinitionRuntime.
+type Option func(*ResourceGraphDefinitionRuntime)
+
+// WithSchemaDefaulter sets the function applying the computed defaults to the
+// instance seen by expressions as "schema". The instance itself is never
+// modified, so the computed values are not written back to the cluster.
+func WithSchemaDefaulter(defaulter func(*unstructured.Unstructured) error) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.schemaDefaulter = defaulter
+	}
+}
+
+// WithReadyExpression sets the expression deciding whether the instance is
+// ready, along with the resources it depends on.
+func WithReadyExpression(expression string, dependencies []string) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.readyExpression = expression
+		rt.readyExpressionDependencies = dependencies
+	}
+}
+
+// schema returns the object expressions refer to as "schema": the instance,
+// with its computed defaults applied.
+func (rt *ResourceGraphDefinitionRuntime) schema() map[string]interface{} {
+	if rt.schemaDefaulter == nil {
+		return rt.instance.Unstructured().Object
+	}
+	instance := rt.instance.Unstructured().DeepCopy()
+	// The defaulter already succeeded on this instance when the runtime was
+	// created.
+	_ = rt.schemaDefaulter(instance)
+	return instance.Object
 }
 
 // TopologicalOrder returns the topological order of resources.
@@ -238,20 +297,24 @@ func (rt *ResourceGraphDefinitionRuntime) Synchronize() (bool, error) {
 		return false, nil
 	}
 
-	// first synchronize the resources.
-	err := rt.evaluateDynamicVariables()
-	if err != nil {
-		return true, fmt.Errorf("failed to evaluate dynamic variables: %w", err)
+	// first synchronize the resources. A failing expression doesn't stop the
+	// resolution of the resources that don't depend on it, so that all the
+	// errors are reported at once.
+	var errs []error
+	if err := rt.evaluateDynamicVariables(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to evaluate dynamic variables: %w", err))
 	}
 
 	// Now propagate the resource variables.
-	err = rt.propagateResourceVariables()
-	if err != nil {
-		return true, fmt.Errorf("failed to propagate resource variables: %w", err)
+	if err := rt.propagateResourceVariables(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to propagate resource variables: %w", err))
+	}
+	if len(errs) > 0 {
+		return true, errors.Join(errs...)
 	}
 
 	// then synchronize the instance
-	err = rt.evaluateInstanceStatuses()
+	err := rt.evaluateInstanceStatuses()
 	if err != nil {
 		return true, fmt.Errorf("failed to evaluate instance statuses: %w", err)
 	}
@@ -260,18 +323,20 @@ func (rt *ResourceGraphDefinitionRuntime) Synchronize() (bool, error) {
 }
 
 // propagateResourceVariables iterates over all resources and evaluates their
-// variables if all dependencies are resolved.
+// variables if all dependencies are resolved. The errors of all the resources
+// are aggregated.
 func (rt *ResourceGraphDefinitionRuntime) propagateResourceVariables() error {
-	for id := range rt.resources {
+	var errs []error
+	for _, id := range sortedKeys(rt.resources) {
 		if rt.canProcessResource(id) {
 			// evaluate the resource variables
 			err := rt.evaluateResourceExpressions(id)
 			if err != nil {
-				return fmt.Errorf("failed to evaluate resource variables for %s: %w", id, err)
+				errs = append(errs, fmt.Errorf("failed to evaluate resource variables for %s: %w", id, err))
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // canProcessResource checks if a resource can be resolved by examining
@@ -312,7 +377,7 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateStaticVariables() error {
 	}
 
 	evalContext := map[string]interface{}{
-		"schema": rt.instance.Unstructured().Object,
+		"schema": rt.schema(),
 	}
 	for _, variable := range rt.expressionsCache {
 		if variable.Kind.IsStatic() {
@@ -359,8 +424,13 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariables() error {
 	// Let's iterate over any resolved resource and try to resolve
 	// the dynamic variables that depend on it.
 	// Since we have already cached the expressions, we don't need to
-	// loop over all the resources.
-	for _, variable := range rt.expressionsCache {
+	// loop over all the resources. The expressions are evaluated in a stable
+	// order, and a failing expression doesn't stop the evaluation of the
+	// other ones: their errors are aggregated.
+	var errs []error
+	schema := rt.schema()
+	for _, key := range sortedKeys(rt.expressionsCache) {
+		variable := rt.expressionsCache[key]
 		if variable.Kind.IsDynamic() {
 			// Skip the variable if it's already resolved
 			if variable.Resolved {
@@ -379,21 +449,17 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariables() error {
 				evalContext[dep] = rt.resolvedResources[dep].Object
 			}
 
-			evalContext["schema"] = rt.instance.Unstructured().Object
+			evalContext["schema"] = schema
 
 			value, err := evaluateExpression(env, evalContext, variable.Expression)
 			if err != nil {
-				if strings.Contains(err.Error(), "no such key") {
-					// TODO(a-hilaly): I'm not sure if this is the best way to handle
-					// these. Probably need to reiterate here.
-					return &EvalError{
-						IsIncompleteData: true,
-						Err:              err,
-					}
-				}
-				return &EvalError{
-					Err: err,
-				}
+				// TODO(a-hilaly): I'm not sure if this is the best way to handle
+				// these. Probably need to reiterate here.
+				errs = append(errs, fmt.Errorf("expression %q: %w", variable.Expression, &EvalError{
+					IsIncompleteData: strings.Contains(err.Error(), "no such key"),
+					Err:              err,
+				}))
+				continue
 			}
 
 			variable.Resolved = true
@@ -401,7 +467,7 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariables() error {
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // evaluateInstanceStatuses updates the status of the main instance based on
@@ -409,7 +475,38 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariables() error {
 // from all managed resources to provide an overall status of the runtime,
 // which is typically reflected in the custom resource's status field.
 func (rt *ResourceGraphDefinitionRuntime) evaluateInstanceStatuses() error {
-	rs := resolver.NewResolver(rt.instance.Unstructured().Object, map[string]interface{}{})
+	return rt.setInstanceStatuses(rt.instance.Unstructured().Object)
+}
+
+// GetInstanceStatus returns the status fields of the instance computed from the
+// instance variables, without the fields set by other controllers. The fields
+// whose variables aren't resolved yet, e.g. because a resource isn't ready,
+// keep the value observed on the instance.
+func (rt *ResourceGraphDefinitionRuntime) GetInstanceStatus() map[string]interface{} {
+	obj := map[string]interface{}{}
+	rs := resolver.NewResolver(obj, map[string]interface{}{})
+	observed := resolver.NewResolver(rt.instance.Unstructured().Object, map[string]interface{}{})
+	for _, variable := range rt.instance.GetVariables() {
+		var value interface{}
+		if cached, ok := rt.expressionsCache[variable.Expressions[0]]; ok && cached.Resolved {
+			value = cached.ResolvedValue
+		} else {
+			var err error
+			if value, err = observed.ValueAtPath(variable.Path); err != nil {
+				continue
+			}
+		}
+		// The paths were validated when the variables were resolved.
+		_ = rs.UpsertValueAtPath(variable.Path, value)
+	}
+	status, _ := obj["status"].(map[string]interface{})
+	return status
+}
+
+// setInstanceStatuses sets the resolved instance variables at their path in
+// the given object.
+func (rt *ResourceGraphDefinitionRuntime) setInstanceStatuses(obj map[string]interface{}) error {
+	rs := resolver.NewResolver(obj, map[string]interface{}{})
 
 	// Two pieces of information are needed here:
 	//  1. Instance variables are guaranteed to be standalone expressions.
@@ -451,7 +548,46 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateResourceExpressions(resource s
 
 	summary := rs.Resolve(exprFields)
 	if summary.Errors != nil {
-		return fmt.Errorf("failed to resolve resource %s: %v", resource, summary.Errors)
+		var errs []error
+		for _, result := range summary.Results {
+			if result.Error != nil {
+				errs = append(errs, fmt.Errorf("path %s: %w", result.Path, result.Error))
+			}
+		}
+		return fmt.Errorf("failed to resolve resource %s: %w", resource, errors.Join(errs...))
+	}
+	return validateResolvedName(resource, rt.resources[resource].Unstructured().Object, exprFields)
+}
+
+// validateResolvedName checks that a templated metadata.name resolved to a
+// possible object name. An empty or malformed name, typically coming from an
+// empty instance field, is reported with the expression that produced it,
+// instead of failing later when the resource is created.
+func validateResolvedName(resource string, obj map[string]interface{}, fields []variable.FieldDescriptor) error {
+	for _, field := range fields {
+		if field.Path != "metadata.name" {
+			continue
+		}
+		expressions := make([]string, len(field.Expressions))
+		for i, expr := range field.Expressions {
+			expressions[i] = "${" + expr + "}"
+		}
+
+		name, _, err := unstructured.NestedString(obj, "metadata", "name")
+		if err != nil {
+			return fmt.Errorf("resource %s: metadata.name resolved from %s must be a string: %w",
+				resource, strings.Join(expressions, ", "), err)
+		}
+		if name == "" {
+			return fmt.Errorf("resource %s: metadata.name resolved to an empty string from %s",
+				resource, strings.Join(expressions, ", "))
+		}
+		// The valid names depend on the kind, e.g. RBAC objects accept colons,
+		// only what no object name can hold is rejected here.
+		if errs := path.IsValidPathSegmentName(name); len(errs) > 0 {
+			return fmt.Errorf("resource %s: metadata.name %q resolved from %s is not a valid name: %s",
+				resource, name, strings.Join(expressions, ", "), strings.Join(errs, "; "))
+		}
 	}
 	return nil
 }
@@ -468,8 +604,8 @@ func (rt *ResourceGraphDefinitionRuntime) allExpressionsAreResolved() bool {
 }
 
 // IsResourceReady checks if a resource is ready based on the readyWhenExpressions
-// defined in the resource. If no readyWhenExpressions are defined, the resource
-// is considered ready.
+// and readyWhenJSONPath checks defined in the resource. If none are defined, the
+// resource is considered ready.
 func (rt *ResourceGraphDefinitionRuntime) IsResourceReady(resourceID string) (bool, string, error) {
 	observed, ok := rt.resolvedResources[resourceID]
 	if !ok {
@@ -478,6 +614,16 @@ func (rt *ResourceGraphDefinitionRuntime) IsResourceReady(resourceID string) (bo
 		return false, fmt.Sprintf("resource %s is not resolved", resourceID), nil
 	}
 
+	for _, check := range rt.resources[resourceID].GetReadyWhenJSONPaths() {
+		value, err := evaluateJSONPath(observed.Object, check.Path)
+		if err != nil {
+			return false, "", fmt.Errorf("failed evaluating JSONPath %s: %w", check.Path, err)
+		}
+		if value != check.Value {
+			return false, fmt.Sprintf("JSONPath %s evaluated to %q, expected %q", check.Path, value, check.Value), nil
+		}
+	}
+
 	expressions := rt.resources[resourceID].GetReadyWhenExpressions()
 	if len(expressions) == 0 {
 		return true, "", nil
@@ -506,6 +652,57 @@ func (rt *ResourceGraphDefinitionRuntime) IsResourceReady(resourceID string) (bo
 	return true, "", nil
 }
 
+// IsInstanceReady evaluates the ready expression against the observed
+// resources. The instance isn't ready as long as one of the resources the
+// expression depends on isn't observed. Resources ignored by their conditions
+// will never be observed, they are null in the expression instead.
+func (rt *ResourceGraphDefinitionRuntime) IsInstanceReady() (bool, string, error) {
+	if rt.readyExpression == "" {
+		return true, "", nil
+	}
+
+	context := make(map[string]interface{}, len(rt.readyExpressionDependencies))
+	for _, dep := range rt.readyExpressionDependencies {
+		if rt.ignoredByConditionsResources[dep] {
+			context[dep] = nil
+			continue
+		}
+		observed, ok := rt.resolvedResources[dep]
+		if !ok {
+			return false, fmt.Sprintf("resource %s is not resolved", dep), nil
+		}
+		context[dep] = observed.Object
+	}
+
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(rt.readyExpressionDependencies))
+	if err != nil {
+		return false, "", fmt.Errorf("failed creating new Environment: %w", err)
+	}
+	out, err := evaluateExpression(env, context, rt.readyExpression)
+	if err != nil {
+		return false, "", fmt.Errorf("failed evaluating ready expression %s: %w", rt.readyExpression, err)
+	}
+	if ready, ok := out.(bool); !ok || !ready {
+		return false, fmt.Sprintf("ready expression %s evaluated to false", rt.readyExpression), nil
+	}
+	return true, "", nil
+}
+
+// evaluateJSONPath evaluates a JSONPath expression against an object and
+// returns its textual result. Missing fields evaluate to an empty string, so
+// that a resource that didn't populate its status yet is simply not ready.
+func evaluateJSONPath(obj map[string]interface{}, path string) (string, error) {
+	jp := jsonpath.New("readyWhenJSONPath").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, obj); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // IgnoreResource ignores resource that has a condition expression that evaluated
 // to false or whose dependencies are ignored
 func (rt *ResourceGraphDefinitionRuntime) IgnoreResource(resourceID string) {
@@ -547,7 +744,7 @@ func (rt *ResourceGraphDefinitionRuntime) ReadyToProcessResource(resourceID stri
 	}
 
 	context := map[string]interface{}{
-		"schema": rt.instance.Unstructured().Object,
+		"schema": rt.schema(),
 	}
 
 	for _, includeWhenExpression := range includeWhenExpressions {
@@ -596,3 +793,10 @@ func containsAllElements[T comparable](outer, inner []T) bool {
 	}
 	return true
 }
+
+// sortedKeys returns the keys of the map in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
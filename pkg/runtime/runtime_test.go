@@ -16,14 +16,19 @@ package runtime
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/google/cel-go/cel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
+	"github.com/kro-run/kro/api/v1alpha1"
 	krocel "github.com/kro-run/kro/pkg/cel"
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
@@ -363,6 +368,179 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	}
 }
 
+// Test_ServerAssignedFieldsInInstanceStatus checks that the instance status
+// reads the observed state of a resource, including the fields assigned by the
+// API server (e.g. a Service clusterIP) that are not part of its template.
+func Test_ServerAssignedFieldsInInstanceStatus(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name": "myapp",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.clusterIP",
+					Expressions:          []string{"service.spec.clusterIP"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"service"},
+			},
+		}),
+	)
+	service := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80)},
+				},
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.name",
+					Expressions:          []string{"schema.spec.name"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+		}),
+	)
+
+	rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{"service": service}, []string{"service"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	if _, state := rt.GetResource("service"); state != ResourceStateResolved {
+		t.Fatalf("Service should be ready for processing, state = %v", state)
+	}
+	if _, found := instance.Unstructured().Object["status"]; found {
+		t.Error("Instance status should not be resolved before the service is observed")
+	}
+
+	// The observed service holds the clusterIP assigned by the API server.
+	rt.SetResource("service", &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "myapp",
+			},
+			"spec": map[string]interface{}{
+				"clusterIP": "10.96.12.34",
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				},
+			},
+		},
+	})
+
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	clusterIP, _, _ := unstructured.NestedString(instance.Unstructured().Object, "status", "clusterIP")
+	if clusterIP != "10.96.12.34" {
+		t.Errorf("Instance status clusterIP = %q, want %q", clusterIP, "10.96.12.34")
+	}
+}
+
+func Test_ComputedFieldsOfDependencies(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name": "myapp",
+			},
+		}),
+	)
+	// The name of the config map is computed by kro, not by the API server.
+	configMap := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}-config",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:        "metadata.name",
+					Expressions: []string{"schema.spec.name"},
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+		}),
+	)
+	deployment := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"configName": "${configmap.metadata.name}",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.name",
+					Expressions:          []string{"schema.spec.name"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "spec.configName",
+					Expressions:          []string{"configmap.metadata.name"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"configmap"},
+			},
+		}),
+		withDependencies([]string{"configmap"}),
+	)
+
+	rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{
+		"configmap":  configMap,
+		"deployment": deployment,
+	}, []string{"configmap", "deployment"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	resolved, state := rt.GetResource("configmap")
+	if state != ResourceStateResolved {
+		t.Fatalf("ConfigMap should be ready for processing, state = %v", state)
+	}
+	if _, state := rt.GetResource("deployment"); state == ResourceStateResolved {
+		t.Fatal("Deployment should wait for the config map to be observed")
+	}
+
+	// The observed config map is the resolved one, plus the fields set by the
+	// API server.
+	observed := resolved.DeepCopy()
+	observed.SetUID("uid")
+	rt.SetResource("configmap", observed)
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	got, state := rt.GetResource("deployment")
+	if state != ResourceStateResolved {
+		t.Fatalf("Deployment should be ready for processing, state = %v", state)
+	}
+	configName, _, _ := unstructured.NestedString(got.Object, "spec", "configName")
+	if configName != "myapp-config" {
+		t.Errorf("Deployment spec.configName = %q, want %q", configName, "myapp-config")
+	}
+}
+
 func Test_NewResourceGraphDefinitionRuntime(t *testing.T) {
 	// Setup a test instance with a spec
 	instance := newTestResource(
@@ -1423,6 +1601,42 @@ func Test_evaluateStaticVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "instance uid and creationTimestamp",
+			instance: newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name":              "test",
+						"uid":               "3f2c7a5e-1b2d-4c8e-9f10-5a6b7c8d9e0f",
+						"creationTimestamp": "2025-03-01T10:00:00Z",
+					},
+				}),
+			),
+			expressionsCache: map[string]*expressionEvaluationState{
+				"expr1": {
+					Expression: "schema.metadata.name + '-' + schema.metadata.uid.split('-')[0]",
+					Kind:       variable.ResourceVariableKindStatic,
+				},
+				"expr2": {
+					Expression: "timestamp(schema.metadata.creationTimestamp) < timestamp('2025-06-01T00:00:00Z')",
+					Kind:       variable.ResourceVariableKindStatic,
+				},
+			},
+			wantCache: map[string]*expressionEvaluationState{
+				"expr1": {
+					Expression:    "schema.metadata.name + '-' + schema.metadata.uid.split('-')[0]",
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: "test-3f2c7a5e",
+				},
+				"expr2": {
+					Expression:    "timestamp(schema.metadata.creationTimestamp) < timestamp('2025-06-01T00:00:00Z')",
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: true,
+				},
+			},
+		},
 		{
 			name: "invalid expression",
 			instance: newTestResource(
@@ -1717,6 +1931,54 @@ func Test_evaluateDynamicVariables(t *testing.T) {
 	}
 }
 
+func Test_evaluateDynamicVariablesAggregatesErrors(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: newTestResource(
+			withObject(map[string]interface{}{}),
+		),
+		expressionsCache: map[string]*expressionEvaluationState{
+			"res1.spec.count + 'a'": {
+				Expression:   "res1.spec.count + 'a'",
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"res1"},
+			},
+			"res2.spec.missing": {
+				Expression:   "res2.spec.missing",
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"res2"},
+			},
+			"res2.spec.count > 0": {
+				Expression:   "res2.spec.count > 0",
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"res2"},
+			},
+		},
+		resolvedResources: map[string]*unstructured.Unstructured{
+			"res1": {Object: map[string]interface{}{"spec": map[string]interface{}{"count": int64(1)}}},
+			"res2": {Object: map[string]interface{}{"spec": map[string]interface{}{"count": int64(2)}}},
+		},
+	}
+
+	err := rt.evaluateDynamicVariables()
+	if err == nil {
+		t.Fatal("evaluateDynamicVariables() expected an error")
+	}
+	// Both failing expressions are reported, not only the first one.
+	for _, expr := range []string{"res1.spec.count + 'a'", "res2.spec.missing"} {
+		if !strings.Contains(err.Error(), fmt.Sprintf("expression %q", expr)) {
+			t.Errorf("evaluateDynamicVariables() error = %v, want it to report %q", err, expr)
+		}
+	}
+	var evalErr *EvalError
+	if !errors.As(err, &evalErr) {
+		t.Errorf("evaluateDynamicVariables() error = %v, want an EvalError", err)
+	}
+	// The independent expression is still resolved.
+	if !rt.expressionsCache["res2.spec.count > 0"].Resolved {
+		t.Errorf("evaluateDynamicVariables() didn't resolve the independent expression")
+	}
+}
+
 func Test_evaluateInstanceStatuses(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1936,6 +2198,57 @@ func Test_evaluateInstanceStatuses(t *testing.T) {
 	}
 }
 
+func Test_GetInstanceStatus(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"status": map[string]interface{}{
+				"ready": false,
+				"count": int64(2),
+				// Set by another controller
+				"external": "value",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.ready",
+					Expressions:          []string{"expr1"},
+					StandaloneExpression: true,
+				},
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.count",
+					Expressions:          []string{"expr2"},
+					StandaloneExpression: true,
+				},
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.endpoint",
+					Expressions:          []string{"expr3"},
+					StandaloneExpression: true,
+				},
+			},
+		}),
+	)
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: instance,
+		expressionsCache: map[string]*expressionEvaluationState{
+			"expr1": {Expression: "expr1", Resolved: true, ResolvedValue: true},
+			"expr2": {Expression: "expr2"},
+			"expr3": {Expression: "expr3"},
+		},
+	}
+
+	// The resolved status variables are returned, the unresolved ones keep
+	// their observed value, if any, and the other fields are left out.
+	want := map[string]interface{}{"ready": true, "count": int64(2)}
+	if got := rt.GetInstanceStatus(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetInstanceStatus() = %v, want %v", got, want)
+	}
+}
+
 func Test_evaluateResourceExpressions(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1943,6 +2256,7 @@ func Test_evaluateResourceExpressions(t *testing.T) {
 		expressions map[string]*expressionEvaluationState
 		wantObj     map[string]interface{}
 		wantErr     bool
+		wantErrMsg  string
 	}{
 		{
 			name: "simple replacement",
@@ -2079,6 +2393,121 @@ func Test_evaluateResourceExpressions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "name resolved to an empty string",
+			resource: newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "metadata.name",
+							Expressions:          []string{"schema.spec.name"},
+							StandaloneExpression: true,
+						},
+					},
+				}),
+			),
+			expressions: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Expression:    "schema.spec.name",
+					Resolved:      true,
+					ResolvedValue: "",
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "resource test: metadata.name resolved to an empty string from ${schema.spec.name}",
+		},
+		{
+			name: "name resolved to an invalid name",
+			resource: newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}-db",
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:        "metadata.name",
+							Expressions: []string{"schema.spec.name"},
+						},
+					},
+				}),
+			),
+			expressions: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Expression:    "schema.spec.name",
+					Resolved:      true,
+					ResolvedValue: "team/app",
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `resource test: metadata.name "team/app-db" resolved from ${schema.spec.name} is not a valid name`,
+		},
+		{
+			name: "name valid for some kinds only",
+			resource: newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "system:${schema.spec.name}",
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:        "metadata.name",
+							Expressions: []string{"schema.spec.name"},
+						},
+					},
+				}),
+			),
+			expressions: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Expression:    "schema.spec.name",
+					Resolved:      true,
+					ResolvedValue: "My_App",
+				},
+			},
+			wantObj: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "system:My_App",
+				},
+			},
+		},
+		{
+			name: "valid name",
+			resource: newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}-db",
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:        "metadata.name",
+							Expressions: []string{"schema.spec.name"},
+						},
+					},
+				}),
+			),
+			expressions: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Expression:    "schema.spec.name",
+					Resolved:      true,
+					ResolvedValue: "my-app",
+				},
+			},
+			wantObj: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "my-app-db",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2093,6 +2522,9 @@ func Test_evaluateResourceExpressions(t *testing.T) {
 				t.Errorf("evaluateResourceExpressions() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErrMsg != "" && !strings.Contains(err.Error(), tt.wantErrMsg) {
+				t.Errorf("evaluateResourceExpressions() error = %v, want %q", err, tt.wantErrMsg)
+			}
 
 			if !tt.wantErr {
 				got := tt.resource.Unstructured().Object
@@ -2264,6 +2696,74 @@ func Test_IsResourceReady(t *testing.T) {
 			want:       false,
 			wantReason: "expression test.status.healthy evaluated to false",
 		},
+		{
+			name: "jsonpath matching value",
+			resource: newTestResource(
+				withReadyJSONPaths([]v1alpha1.ReadyWhenJSONPath{
+					{Path: "{.status.phase}", Value: "Running"},
+				}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"phase": "Running",
+				},
+			},
+			want: true,
+		},
+		{
+			name: "jsonpath non matching value",
+			resource: newTestResource(
+				withReadyJSONPaths([]v1alpha1.ReadyWhenJSONPath{
+					{Path: "{.status.phase}", Value: "Running"},
+				}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"phase": "Pending",
+				},
+			},
+			want:       false,
+			wantReason: `JSONPath {.status.phase} evaluated to "Pending", expected "Running"`,
+		},
+		{
+			name: "jsonpath missing field",
+			resource: newTestResource(
+				withReadyJSONPaths([]v1alpha1.ReadyWhenJSONPath{
+					{Path: "{.status.phase}", Value: "Running"},
+				}),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+			wantReason:     `JSONPath {.status.phase} evaluated to "", expected "Running"`,
+		},
+		{
+			name: "jsonpath matching with false ready expression",
+			resource: newTestResource(
+				withReadyJSONPaths([]v1alpha1.ReadyWhenJSONPath{
+					{Path: "{.status.phase}", Value: "Running"},
+				}),
+				withReadyExpressions([]string{"test.status.ready"}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"phase": "Running",
+					"ready": false,
+				},
+			},
+			want:       false,
+			wantReason: "expression test.status.ready evaluated to false",
+		},
+		{
+			name: "invalid jsonpath",
+			resource: newTestResource(
+				withReadyJSONPaths([]v1alpha1.ReadyWhenJSONPath{
+					{Path: "{.status.phase", Value: "Running"},
+				}),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+			wantErr:        true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2291,6 +2791,85 @@ func Test_IsResourceReady(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsInstanceReady(t *testing.T) {
+	const expression = "deployment.status.ready && database.status.ready"
+	dependencies := []string{"deployment", "database"}
+	status := func(ready bool) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": ready},
+		}}
+	}
+
+	tests := []struct {
+		name              string
+		readyExpression   string
+		resolvedResources map[string]*unstructured.Unstructured
+		ignoredResources  map[string]bool
+		want              bool
+		wantReason        string
+	}{
+		{
+			name: "no ready expression",
+			want: true,
+		},
+		{
+			name:              "ignored dependency is null",
+			readyExpression:   "deployment.status.ready && (database == null || database.status.ready)",
+			resolvedResources: map[string]*unstructured.Unstructured{"deployment": status(true)},
+			ignoredResources:  map[string]bool{"database": true},
+			want:              true,
+		},
+		{
+			name:              "dependency not resolved",
+			readyExpression:   expression,
+			resolvedResources: map[string]*unstructured.Unstructured{"deployment": status(true)},
+			want:              false,
+			wantReason:        "resource database is not resolved",
+		},
+		{
+			name:            "one resource not ready",
+			readyExpression: expression,
+			resolvedResources: map[string]*unstructured.Unstructured{
+				"deployment": status(true),
+				"database":   status(false),
+			},
+			want:       false,
+			wantReason: "ready expression " + expression + " evaluated to false",
+		},
+		{
+			name:            "all resources ready",
+			readyExpression: expression,
+			resolvedResources: map[string]*unstructured.Unstructured{
+				"deployment": status(true),
+				"database":   status(true),
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ResourceGraphDefinitionRuntime{
+				resolvedResources:            tt.resolvedResources,
+				ignoredByConditionsResources: tt.ignoredResources,
+			}
+			WithReadyExpression(tt.readyExpression, dependencies)(rt)
+
+			got, reason, err := rt.IsInstanceReady()
+			if err != nil {
+				t.Fatalf("IsInstanceReady() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsInstanceReady() = %v, want %v", got, tt.want)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("IsInstanceReady() reason = %v, want %v", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
 func Test_ReadyToProcessResource(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2545,6 +3124,58 @@ func Test_evaluateExpression(t *testing.T) {
 			expression: "data.?value",
 			wantErr:    true,
 		},
+		{
+			name: "missing optional field",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{},
+			},
+			expression: "data.?value",
+			want:       nil,
+		},
+		{
+			name: "map converted to an env list",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{
+					"env": map[string]interface{}{
+						"LOG_LEVEL": "debug",
+						"DB_HOST":   "db.default.svc",
+					},
+				},
+			},
+			expression: "toEnvList(data.env)",
+			want: []interface{}{
+				map[string]interface{}{"name": "DB_HOST", "value": "db.default.svc"},
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+			},
+		},
+		{
+			name: "env list from a map with non string values",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{
+					"env": map[string]interface{}{
+						"REPLICAS": int64(3),
+					},
+				},
+			},
+			expression: "toEnvList(data.env)",
+			wantErr:    true,
+		},
+		{
+			name: "list of objects mapped to container ports",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"name": "http", "port": int64(8080)},
+						map[string]interface{}{"name": "metrics", "port": int64(9090)},
+					},
+				},
+			},
+			expression: "data.ports.map(p, {'name': p.name, 'containerPort': p.port, 'protocol': 'TCP'})",
+			want: []interface{}{
+				map[string]interface{}{"name": "http", "containerPort": int64(8080), "protocol": "TCP"},
+				map[string]interface{}{"name": "metrics", "containerPort": int64(9090), "protocol": "TCP"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2645,6 +3276,7 @@ type mockResource struct {
 	variables              []*variable.ResourceField
 	dependencies           []string
 	readyExpressions       []string
+	readyJSONPaths         []v1alpha1.ReadyWhenJSONPath
 	includeWhenExpressions []string
 	namespaced             bool
 	isExternalRef          bool
@@ -2675,6 +3307,10 @@ func (m *mockResource) GetReadyWhenExpressions() []string {
 	return m.readyExpressions
 }
 
+func (m *mockResource) GetReadyWhenJSONPaths() []v1alpha1.ReadyWhenJSONPath {
+	return m.readyJSONPaths
+}
+
 func (m *mockResource) GetIncludeWhenExpressions() []string {
 	return m.includeWhenExpressions
 }
@@ -2691,6 +3327,22 @@ func (m *mockResource) IsExternalRef() bool {
 	return m.isExternalRef
 }
 
+func (m *mockResource) DeleteWithInstance() bool {
+	return false
+}
+
+func (m *mockResource) ReconcileOnce() bool {
+	return false
+}
+
+func (m *mockResource) DeletionPropagation() metav1.DeletionPropagation {
+	return ""
+}
+
+func (m *mockResource) GetSchema() *spec.Schema {
+	return nil
+}
+
 type mockResourceOption func(*mockResource)
 
 /* func withGVR(group, version, resource string) mockResourceOption {
@@ -2721,6 +3373,12 @@ func withReadyExpressions(exprs []string) mockResourceOption {
 	}
 }
 
+func withReadyJSONPaths(checks []v1alpha1.ReadyWhenJSONPath) mockResourceOption {
+	return func(m *mockResource) {
+		m.readyJSONPaths = checks
+	}
+}
+
 func withIncludeWhenExpressions(exprs []string) mockResourceOption {
 	return func(m *mockResource) {
 		m.includeWhenExpressions = exprs
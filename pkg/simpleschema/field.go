@@ -49,8 +49,9 @@ func parseFieldSchema(fieldSchema string) (string, []*Marker, error) {
 		return typ, nil, nil
 	}
 
-	// trim spaces from the markers
-	markers, err := parseMarkers(strings.TrimSpace(parts[1]))
+	// trim spaces from the markers, unknown markers are handled by the
+	// transformer.
+	markers, err := splitMarkers(strings.TrimSpace(parts[1]))
 	if err != nil {
 		return "", nil, err
 	}
@@ -69,6 +69,10 @@ const (
 	MarkerTypeMinItems MarkerType = "minItems"
 	// MarkerTypeMaxItems represents the `maxItems` marker.
 	MarkerTypeMaxItems MarkerType = "maxItems"
+	// MarkerTypeDeprecated represents the `deprecated` marker.
+	MarkerTypeDeprecated MarkerType = "deprecated"
+	// MarkerTypeSensitive represents the `sensitive` marker.
+	MarkerTypeSensitive MarkerType = "sensitive"
 )
 
 func markerTypeFromString(s string) (MarkerType, error) {
@@ -76,7 +80,7 @@ func markerTypeFromString(s string) (MarkerType, error) {
 	case MarkerTypeRequired, MarkerTypeDefault, MarkerTypeDescription,
 		MarkerTypeMinimum, MarkerTypeMaximum, MarkerTypeValidation, MarkerTypeEnum, MarkerTypeImmutable,
 		MarkerTypePattern, MarkerTypeUniqueItems, MarkerTypeMinLength, MarkerTypeMaxLength, MarkerTypeMinItems,
-		MarkerTypeMaxItems:
+		MarkerTypeMaxItems, MarkerTypeDeprecated, MarkerTypeSensitive:
 		return MarkerType(s), nil
 	default:
 		return "", fmt.Errorf("unknown marker type: %s", s)
@@ -90,10 +94,15 @@ type Marker struct {
 	Value      string
 }
 
-// parseMarker parses a marker string and returns a `Marker` struct.
-// The marker string should be in the format `marker=value`.
-// parseMarkers parses a string of markers and returns a slice of Marker structs
-func parseMarkers(markers string) ([]*Marker, error) {
+// isKnownMarker returns true if the marker is one of the supported marker types.
+func isKnownMarker(marker *Marker) bool {
+	_, err := markerTypeFromString(marker.Key)
+	return err == nil
+}
+
+// splitMarkers splits a string of markers, in the format `marker=value`, into
+// a slice of Marker structs. The marker keys are not checked, see isKnownMarker.
+func splitMarkers(markers string) ([]*Marker, error) {
 	var result []*Marker
 	var currentMarker *Marker
 	var inQuotes bool
@@ -108,11 +117,7 @@ func parseMarkers(markers string) ([]*Marker, error) {
 			if key == "" {
 				return nil, fmt.Errorf("empty marker key")
 			}
-			markerType, err := markerTypeFromString(key)
-			if err != nil {
-				return nil, fmt.Errorf("invalid marker key '%s': %v", key, err)
-			}
-			currentMarker = &Marker{MarkerType: markerType, Key: key}
+			currentMarker = &Marker{MarkerType: MarkerType(key), Key: key}
 			buffer.Reset()
 		case char == '"' && !escaped:
 			inQuotes = !inQuotes
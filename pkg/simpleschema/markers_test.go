@@ -19,7 +19,7 @@ import (
 	"testing"
 )
 
-func TestParseMarkers(t *testing.T) {
+func TestSplitMarkers(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   string
@@ -27,10 +27,12 @@ func TestParseMarkers(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name:    "Invalid marker key",
-			input:   "invalid=true",
-			want:    nil,
-			wantErr: true,
+			name:  "unknown marker key is kept",
+			input: "invalid=true",
+			want: []*Marker{
+				{MarkerType: "invalid", Key: "invalid", Value: "true"},
+			},
+			wantErr: false,
 		},
 		{
 			name:    "unclosed quote in value",
@@ -251,13 +253,36 @@ func TestParseMarkers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseMarkers(tt.input)
+			got, err := splitMarkers(tt.input)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseMarkers() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("splitMarkers() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("parseMarkers() = %v, want %v", got, tt.want)
+				t.Errorf("splitMarkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKnownMarker(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "required", key: "required", want: true},
+		{name: "default", key: "default", want: true},
+		{name: "sensitive", key: "sensitive", want: true},
+		{name: "unknown key", key: "invalid", want: false},
+		{name: "wrong case", key: "Required", want: false},
+		{name: "empty key", key: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKnownMarker(&Marker{Key: tt.key}); got != tt.want {
+				t.Errorf("isKnownMarker(%q) = %v, want %v", tt.key, got, tt.want)
 			}
 		})
 	}
@@ -28,8 +28,10 @@ import (
 // The second input customTypes is a map[string]interface{} where the key is
 // the type name and the value its specification. These custom types will be
 // available as predefined types in the transformer.
-func ToOpenAPISpec(obj map[string]interface{}, customTypes map[string]interface{}) (*extv1.JSONSchemaProps, error) {
-	tf := newTransformer()
+//
+// Unknown markers are an error, unless WithLenientMarkers is given.
+func ToOpenAPISpec(obj map[string]interface{}, customTypes map[string]interface{}, opts ...Option) (*extv1.JSONSchemaProps, error) {
+	tf := newTransformer(opts...)
 	if err := tf.loadPreDefinedTypes(customTypes); err != nil {
 		return nil, err
 	}
@@ -15,7 +15,9 @@
 package simpleschema
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
 	"slices"
 	"strconv"
@@ -46,13 +48,46 @@ type predefinedType struct {
 // transformer is a transformer for OpenAPI schemas
 type transformer struct {
 	preDefinedTypes map[string]predefinedType
+	// warnUnknownMarker, when set, is called for each unknown marker, which
+	// is then ignored. Otherwise unknown markers are an error.
+	warnUnknownMarker func(message string)
+	// markSensitive, when set, is called with the path of each field
+	// carrying the sensitive marker.
+	markSensitive func(path string)
+	// loadingTypes is set while the custom types are transformed.
+	loadingTypes bool
+	// path is the path of the field being transformed.
+	path []string
 }
 
 // newTransformer creates a new transformer
-func newTransformer() *transformer {
-	return &transformer{
+func newTransformer(opts ...Option) *transformer {
+	tf := &transformer{
 		preDefinedTypes: make(map[string]predefinedType),
 	}
+	for _, opt := range opts {
+		opt(tf)
+	}
+	return tf
+}
+
+// Option configures the transformation of a SimpleSchema.
+type Option func(*transformer)
+
+// WithLenientMarkers makes the transformation ignore the unknown markers
+// instead of failing, warn is called with a message for each of them.
+func WithLenientMarkers(warn func(message string)) Option {
+	return func(tf *transformer) {
+		tf.warnUnknownMarker = warn
+	}
+}
+
+// WithSensitiveFields makes the transformation call mark with the dotted path
+// of each field marked as sensitive.
+func WithSensitiveFields(mark func(path string)) Option {
+	return func(tf *transformer) {
+		tf.markSensitive = mark
+	}
 }
 
 // loadPreDefinedTypes loads pre-defined types into the transformer.
@@ -63,6 +98,11 @@ func newTransformer() *transformer {
 func (t *transformer) loadPreDefinedTypes(obj map[string]interface{}) error {
 	t.preDefinedTypes = make(map[string]predefinedType)
 
+	// Sensitive fields are tracked by their path in the spec, which the
+	// fields of the types don't have: the sensitive marker is rejected there.
+	t.loadingTypes = true
+	defer func() { t.loadingTypes = false }()
+
 	jsonSchemaProps, err := t.buildOpenAPISchema(obj)
 	if err != nil {
 		return fmt.Errorf("failed to build pre-defined types schema: %w", err)
@@ -88,7 +128,9 @@ func (tf *transformer) buildOpenAPISchema(obj map[string]interface{}) (*extv1.JS
 	childHasDefault := false
 
 	for key, value := range obj {
+		tf.path = append(tf.path, key)
 		fieldSchema, err := tf.transformField(key, value, schema)
+		tf.path = tf.path[:len(tf.path)-1]
 		if err != nil {
 			return nil, err
 		}
@@ -115,6 +157,8 @@ func (tf *transformer) transformField(
 		return tf.buildOpenAPISchema(nMap)
 	case map[string]interface{}:
 		return tf.buildOpenAPISchema(v)
+	case []interface{}:
+		return tf.buildArrayOfObjectsSchema(key, v)
 	case string:
 		return tf.parseFieldSchema(key, v, parentSchema)
 	default:
@@ -122,11 +166,50 @@ func (tf *transformer) transformField(
 	}
 }
 
+// buildArrayOfObjectsSchema builds the schema of an array of objects declared
+// inline, as a list holding the fields of its items:
+//
+//	ports:
+//	  - name: string | required=true
+//	    port: integer | default=80
+func (tf *transformer) buildArrayOfObjectsSchema(key string, value []interface{}) (*extv1.JSONSchemaProps, error) {
+	if len(value) != 1 {
+		return nil, fmt.Errorf("array of objects %s must declare the fields of its items as a single list element, got %d",
+			key, len(value))
+	}
+
+	var itemSchema *extv1.JSONSchemaProps
+	var err error
+	switch item := value[0].(type) {
+	case map[interface{}]interface{}:
+		itemSchema, err = tf.buildOpenAPISchema(transformMap(item))
+	case map[string]interface{}:
+		itemSchema, err = tf.buildOpenAPISchema(item)
+	default:
+		return nil, fmt.Errorf("array of objects %s must declare the fields of its items, got %v", key, value[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The defaults of the item fields apply to each item, the items
+	// themselves are never defaulted.
+	itemSchema.Default = nil
+
+	return &extv1.JSONSchemaProps{
+		Type:  keyTypeArray,
+		Items: &extv1.JSONSchemaPropsOrArray{Schema: itemSchema},
+	}, nil
+}
+
 func (tf *transformer) parseFieldSchema(key, fieldValue string, parentSchema *extv1.JSONSchemaProps) (*extv1.JSONSchemaProps, error) {
 	fieldType, markers, err := parseFieldSchema(fieldValue)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse field schema for %s: %v", key, err)
 	}
+	markers, err = tf.checkMarkers(markers)
+	if err != nil {
+		return nil, err
+	}
 
 	fieldJSONSchemaProps := &extv1.JSONSchemaProps{}
 
@@ -164,6 +247,24 @@ func (tf *transformer) parseFieldSchema(key, fieldValue string, parentSchema *ex
 	return fieldJSONSchemaProps, nil
 }
 
+// checkMarkers rejects the unknown markers, or drops them with a warning if
+// the transformer is lenient. A misspelled marker would otherwise silently
+// drop a constraint.
+func (tf *transformer) checkMarkers(markers []*Marker) ([]*Marker, error) {
+	known := make([]*Marker, 0, len(markers))
+	for _, marker := range markers {
+		if isKnownMarker(marker) {
+			known = append(known, marker)
+			continue
+		}
+		if tf.warnUnknownMarker == nil {
+			return nil, fmt.Errorf("unknown marker %q on field %s", marker.Key, strings.Join(tf.path, "."))
+		}
+		tf.warnUnknownMarker(fmt.Sprintf("ignoring unknown marker %q on field %s", marker.Key, strings.Join(tf.path, ".")))
+	}
+	return known, nil
+}
+
 func (tf *transformer) handleMapType(key, fieldType string) (*extv1.JSONSchemaProps, error) {
 	keyType, valueType, err := parseMapType(fieldType)
 	if err != nil {
@@ -218,6 +319,9 @@ func (tf *transformer) handleSliceType(key, fieldType string) (*extv1.JSONSchema
 		fieldJSONSchemaProps.Items.Schema = elementSchema
 	} else if isAtomicType(elementType) {
 		fieldJSONSchemaProps.Items.Schema.Type = elementType
+	} else if elementType == keyTypeObject {
+		fieldJSONSchemaProps.Items.Schema.Type = elementType
+		fieldJSONSchemaProps.Items.Schema.XPreserveUnknownFields = ptr.To(true)
 	} else if preDefinedType, ok := tf.preDefinedTypes[elementType]; ok {
 		fieldJSONSchemaProps.Items.Schema = &preDefinedType.Schema
 	} else {
@@ -229,6 +333,7 @@ func (tf *transformer) handleSliceType(key, fieldType string) (*extv1.JSONSchema
 
 //nolint:gocyclo
 func (tf *transformer) applyMarkers(schema *extv1.JSONSchemaProps, markers []*Marker, key string, parentSchema *extv1.JSONSchemaProps) error {
+	var deprecation string
 	for _, marker := range markers {
 		switch marker.MarkerType {
 		case MarkerTypeRequired:
@@ -388,11 +493,69 @@ func (tf *transformer) applyMarkers(schema *extv1.JSONSchemaProps, markers []*Ma
 				return fmt.Errorf("failed to parse maxItems value: %w", err)
 			}
 			schema.MaxItems = &val
+		case MarkerTypeDeprecated:
+			if strings.TrimSpace(marker.Value) == "" {
+				return fmt.Errorf("deprecated marker value cannot be empty")
+			}
+			deprecation = marker.Value
+		case MarkerTypeSensitive:
+			isSensitive, err := strconv.ParseBool(marker.Value)
+			if err != nil {
+				return fmt.Errorf("failed to parse sensitive marker value: %w", err)
+			}
+			if isSensitive && tf.loadingTypes {
+				return fmt.Errorf("sensitive marker is not supported on the fields of custom types, "+
+					"mark the spec fields using the type as sensitive instead: %s", strings.Join(tf.path, "."))
+			}
+			if isSensitive && tf.markSensitive != nil {
+				tf.markSensitive(strings.Join(tf.path, "."))
+			}
 		}
 	}
+
+	// Reject a default outside of the enum when the RGD is built, with an
+	// error naming the field, rather than when the generated CRD is applied.
+	if err := validateEnumDefault(schema); err != nil {
+		return fmt.Errorf("invalid default of field %s: %w", strings.Join(tf.path, "."), err)
+	}
+
+	// CRD schemas have no deprecation flag for fields, the deprecation is
+	// added to the field description, which is surfaced by `kubectl explain`.
+	// It is applied last, so it doesn't depend on the order of the markers.
+	if deprecation != "" {
+		if schema.Description != "" {
+			schema.Description += "\n\n"
+		}
+		schema.Description += "Deprecated: " + deprecation
+	}
 	return nil
 }
 
+// validateEnumDefault returns an error if the schema has both a default and an
+// enum, and the default isn't one of the enum values.
+func validateEnumDefault(schema *extv1.JSONSchemaProps) error {
+	if schema == nil || schema.Default == nil || len(schema.Enum) == 0 {
+		return nil
+	}
+	var defaultValue interface{}
+	if err := json.Unmarshal(schema.Default.Raw, &defaultValue); err != nil {
+		return fmt.Errorf("failed to parse default value: %w", err)
+	}
+	values := make([]string, 0, len(schema.Enum))
+	for _, enum := range schema.Enum {
+		var value interface{}
+		if err := json.Unmarshal(enum.Raw, &value); err != nil {
+			return fmt.Errorf("failed to parse enum value: %w", err)
+		}
+		if reflect.DeepEqual(defaultValue, value) {
+			return nil
+		}
+		values = append(values, string(enum.Raw))
+	}
+	return fmt.Errorf("default value %s is not one of the enum values %s",
+		schema.Default.Raw, strings.Join(values, ", "))
+}
+
 // Other functions (LoadPreDefinedTypes, transformMap) remain unchanged
 func transformMap(original map[interface{}]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/utils/ptr"
 )
@@ -366,6 +367,22 @@ func TestBuildOpenAPISchema(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "string default outside of the enum",
+			obj: map[string]interface{}{
+				"logLevel": "string | enum=\"a,b\" default=\"c\"",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "integer default outside of the enum",
+			obj: map[string]interface{}{
+				"errorCode": "integer | enum=\"400,404\" default=500",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "invalid string enum marker",
 			obj: map[string]interface{}{
@@ -544,6 +561,46 @@ func TestBuildOpenAPISchema(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Deprecated field",
+			obj: map[string]interface{}{
+				"size": `string | deprecated="use replicas instead"`,
+			},
+			want: &extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"size": {
+						Type:        "string",
+						Description: "Deprecated: use replicas instead",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Deprecated field with description",
+			obj: map[string]interface{}{
+				"size": `string | deprecated="use replicas instead" description="Size of the cluster"`,
+			},
+			want: &extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"size": {
+						Type:        "string",
+						Description: "Size of the cluster\n\nDeprecated: use replicas instead",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Empty deprecation",
+			obj: map[string]interface{}{
+				"size": `string | deprecated=""`,
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Invalid immutable value",
 			obj: map[string]interface{}{
@@ -922,6 +979,119 @@ func TestBuildOpenAPISchema(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Array of objects",
+			obj: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{
+						"name": "string | required=true",
+						"port": "integer | default=80",
+					},
+				},
+			},
+			want: &extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"ports": {
+						Type: "array",
+						Items: &extv1.JSONSchemaPropsOrArray{
+							Schema: &extv1.JSONSchemaProps{
+								Type:     "object",
+								Required: []string{"name"},
+								Properties: map[string]extv1.JSONSchemaProps{
+									"name": {Type: "string"},
+									"port": {
+										Type:    "integer",
+										Default: &extv1.JSON{Raw: []byte("80")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Array of nested objects",
+			obj: map[string]interface{}{
+				"containers": []interface{}{
+					map[interface{}]interface{}{
+						"image": "string",
+						"ports": []interface{}{
+							map[interface{}]interface{}{
+								"containerPort": "integer",
+							},
+						},
+					},
+				},
+			},
+			want: &extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"containers": {
+						Type: "array",
+						Items: &extv1.JSONSchemaPropsOrArray{
+							Schema: &extv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]extv1.JSONSchemaProps{
+									"image": {Type: "string"},
+									"ports": {
+										Type: "array",
+										Items: &extv1.JSONSchemaPropsOrArray{
+											Schema: &extv1.JSONSchemaProps{
+												Type: "object",
+												Properties: map[string]extv1.JSONSchemaProps{
+													"containerPort": {Type: "integer"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Array of unstructured objects",
+			obj: map[string]interface{}{
+				"values": "[]object",
+			},
+			want: &extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"values": {
+						Type: "array",
+						Items: &extv1.JSONSchemaPropsOrArray{
+							Schema: &extv1.JSONSchemaProps{
+								Type:                   "object",
+								XPreserveUnknownFields: ptr.To(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Array of objects with several item declarations",
+			obj: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"name": "string"},
+					map[string]interface{}{"port": "integer"},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Array of objects without fields",
+			obj: map[string]interface{}{
+				"ports": []interface{}{"string"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Empty pattern value",
 			obj: map[string]interface{}{
@@ -947,6 +1117,87 @@ func TestBuildOpenAPISchema(t *testing.T) {
 	}
 }
 
+func TestUnknownMarkers(t *testing.T) {
+	obj := map[string]interface{}{
+		"database": map[string]interface{}{
+			"port": "integer | minimun=1024 maximum=65535",
+		},
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := ToOpenAPISpec(obj, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown marker "minimun" on field database.port`)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		var warnings []string
+		got, err := ToOpenAPISpec(obj, nil, WithLenientMarkers(func(message string) {
+			warnings = append(warnings, message)
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{`ignoring unknown marker "minimun" on field database.port`}, warnings)
+
+		port := got.Properties["database"].Properties["port"]
+		assert.Equal(t, "integer", port.Type)
+		assert.Nil(t, port.Minimum)
+		assert.Equal(t, ptr.To(65535.0), port.Maximum)
+	})
+}
+
+func TestEnumDefault(t *testing.T) {
+	_, err := ToOpenAPISpec(map[string]interface{}{
+		"logging": map[string]interface{}{
+			"level": "string | enum=\"a,b\" default=\"c\"",
+		},
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid default of field logging.level: default value "c" is not one of the enum values "a", "b"`)
+
+	got, err := ToOpenAPISpec(map[string]interface{}{
+		"errorCode": "integer | enum=\"400,404\" default=404",
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "404", string(got.Properties["errorCode"].Default.Raw))
+}
+
+func TestSensitiveFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"name": "string",
+		"database": map[string]interface{}{
+			"password": "string | sensitive=true",
+			"user":     "string | sensitive=false",
+		},
+		"credentials": "Credentials | sensitive=true",
+	}
+	customTypes := map[string]interface{}{
+		"Credentials": map[string]interface{}{
+			"token": "string",
+		},
+	}
+
+	var sensitive []string
+	got, err := ToOpenAPISpec(obj, customTypes, WithSensitiveFields(func(path string) {
+		sensitive = append(sensitive, path)
+	}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"database.password", "credentials"}, sensitive)
+	assert.Equal(t, "string", got.Properties["database"].Properties["password"].Type)
+
+	_, err = ToOpenAPISpec(map[string]interface{}{"password": "string | sensitive=maybe"}, nil)
+	require.Error(t, err)
+
+	// The fields of the custom types have no path in the spec, the sensitive
+	// marker can't be tracked there.
+	_, err = ToOpenAPISpec(map[string]interface{}{"credentials": "Credentials"}, map[string]interface{}{
+		"Credentials": map[string]interface{}{
+			"token": "string | sensitive=true",
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sensitive marker is not supported on the fields of custom types")
+}
+
 func TestApplyMarkers_Required(t *testing.T) {
 	transformer := newTransformer()
 
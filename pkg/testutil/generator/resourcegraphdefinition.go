@@ -111,6 +111,17 @@ func WithResource(
 	}
 }
 
+// WithTypes sets the custom types of the ResourceGraphDefinition schema.
+func WithTypes(types map[string]interface{}) ResourceGraphDefinitionOption {
+	raw, err := json.Marshal(types)
+	if err != nil {
+		panic(err)
+	}
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		rgd.Spec.Schema.Types = runtime.RawExtension{Raw: raw}
+	}
+}
+
 func WithValidation(expression, message string) ResourceGraphDefinitionOption {
 	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
 		rgd.Spec.Schema.Validation = append(rgd.Spec.Schema.Validation, krov1alpha1.Validation{
@@ -119,3 +130,57 @@ func WithValidation(expression, message string) ResourceGraphDefinitionOption {
 		})
 	}
 }
+
+// WithMutuallyExclusive adds a group of mutually exclusive spec fields.
+func WithMutuallyExclusive(fields ...string) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		rgd.Spec.Schema.MutuallyExclusive = append(rgd.Spec.Schema.MutuallyExclusive, fields)
+	}
+}
+
+// WithReadyExpression sets the expression deciding the instance readiness.
+func WithReadyExpression(expression string) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		rgd.Spec.ReadyExpression = expression
+	}
+}
+
+// WithComputedDefault sets the expression computing the default value of a
+// spec field.
+func WithComputedDefault(field, expression string) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		if rgd.Spec.Schema.ComputedDefaults == nil {
+			rgd.Spec.Schema.ComputedDefaults = map[string]string{}
+		}
+		rgd.Spec.Schema.ComputedDefaults[field] = expression
+	}
+}
+
+// WithReadyGate sets the IDs of the resources gating the instance readiness.
+func WithReadyGate(ids ...string) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		rgd.Spec.ReadyGate = ids
+	}
+}
+
+// WithOverlay adds an overlay variant, selected when the given spec field has
+// the given value, patching the templates of the resources it is keyed by.
+func WithOverlay(field, value string, patches map[string]map[string]interface{}) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		if rgd.Spec.Overlays == nil {
+			rgd.Spec.Overlays = &krov1alpha1.Overlays{
+				Field:    field,
+				Variants: map[string]map[string]runtime.RawExtension{},
+			}
+		}
+		variant := map[string]runtime.RawExtension{}
+		for id, patch := range patches {
+			raw, err := json.Marshal(patch)
+			if err != nil {
+				panic(err)
+			}
+			variant[id] = runtime.RawExtension{Raw: raw}
+		}
+		rgd.Spec.Overlays.Variants[value] = variant
+	}
+}
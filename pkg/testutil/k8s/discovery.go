@@ -349,6 +349,23 @@ func NewFakeResolver() (*FakeResolver, *fake.FakeDiscovery) {
 													Properties: map[string]spec.Schema{
 														"name":  {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
 														"image": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+														"ports": {
+															SchemaProps: spec.SchemaProps{
+																Type: []string{"array"},
+																Items: &spec.SchemaOrArray{
+																	Schema: &spec.Schema{
+																		SchemaProps: spec.SchemaProps{
+																			Type: []string{"object"},
+																			Properties: map[string]spec.Schema{
+																				"name":          {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+																				"containerPort": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+																				"protocol":      {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+																			},
+																		},
+																	},
+																},
+															},
+														},
 														"env": {
 															SchemaProps: spec.SchemaProps{
 																Type: []string{"array"},
@@ -387,6 +404,25 @@ func NewFakeResolver() (*FakeResolver, *fake.FakeDiscovery) {
 				},
 			},
 		},
+		{Version: "v1", Kind: "Secret"}: {
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiVersion": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+					"kind":       {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+					"metadata":   metadataSchema(),
+					"type":       {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+					"stringData": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+							},
+						},
+					},
+				},
+			},
+		},
 		// CRDs
 		{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}: {
 			SchemaProps: spec.SchemaProps{
@@ -490,6 +526,12 @@ func NewFakeResolver() (*FakeResolver, *fake.FakeDiscovery) {
 					Kind:       "Pod",
 					Verbs:      []string{"get", "list", "watch", "create", "update", "patch", "delete"},
 				},
+				{
+					Name:       "secrets",
+					Namespaced: true,
+					Kind:       "Secret",
+					Verbs:      []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+				},
 			},
 		},
 		// CRD
@@ -0,0 +1,192 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tree discovers the objects spawned by an instance, following the
+// labels kro sets on the resources it creates. With nested resource graph
+// definitions, an instance can create resource graph definitions and
+// instances, which create further resources: the result is a tree.
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+var rgdGVR = v1alpha1.GroupVersion.WithResource("resourcegraphdefinitions")
+
+// Node is an object of the tree spawned by an instance.
+type Node struct {
+	// GroupVersionKind is the group, version and kind of the object.
+	schema.GroupVersionKind
+	// Namespace is the namespace of the object, empty for cluster scoped
+	// objects.
+	Namespace string
+	// Name is the name of the object.
+	Name string
+	// ResourceGraphDefinition is the name of the resource graph definition
+	// of the object, if it is an instance.
+	ResourceGraphDefinition string
+	// Children are the objects created by the instance, sorted by kind,
+	// namespace and name. Only instances have children.
+	Children []*Node
+}
+
+// IsInstance returns true if the node is an instance of a resource graph
+// definition.
+func (n *Node) IsInstance() bool {
+	return n.ResourceGraphDefinition != ""
+}
+
+// Build returns the tree of the objects spawned by the instance: the resources
+// it created, and recursively the resources created by the instances among
+// them.
+//
+// The resources of an instance are the objects labeled with its uid, whose
+// kinds are among the resources of its resource graph definition. The mapper
+// resolves these kinds to resources.
+func Build(
+	ctx context.Context,
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	instance *unstructured.Unstructured,
+) (*Node, error) {
+	b := &builder{
+		client:  client,
+		mapper:  mapper,
+		visited: make(map[types.UID]struct{}),
+	}
+	return b.build(ctx, instance)
+}
+
+type builder struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+	// visited holds the uids of the instances already walked through.
+	visited map[types.UID]struct{}
+}
+
+func (b *builder) build(ctx context.Context, instance *unstructured.Unstructured) (*Node, error) {
+	rgdName := instance.GetLabels()[metadata.ResourceGraphDefinitionNameLabel]
+	if rgdName == "" {
+		return nil, fmt.Errorf("%s %s/%s is not an instance: missing %s label",
+			instance.GetKind(), instance.GetNamespace(), instance.GetName(), metadata.ResourceGraphDefinitionNameLabel)
+	}
+	node := newNode(instance)
+	node.ResourceGraphDefinition = rgdName
+
+	if _, ok := b.visited[instance.GetUID()]; ok {
+		return node, nil
+	}
+	b.visited[instance.GetUID()] = struct{}{}
+
+	gvrs, err := b.resourceGVRs(ctx, rgdName)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", metadata.InstanceIDLabel, instance.GetUID()),
+	}
+	for _, gvr := range gvrs {
+		list, err := b.client.Resource(gvr).List(ctx, selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s of %s: %w", gvr.Resource, rgdName, err)
+		}
+		for i := range list.Items {
+			child := &list.Items[i]
+			// The controller of a child instance labels it with its own
+			// resource graph definition.
+			childRGD := child.GetLabels()[metadata.ResourceGraphDefinitionNameLabel]
+			if childRGD == "" || childRGD == rgdName {
+				node.Children = append(node.Children, newNode(child))
+				continue
+			}
+			childNode, err := b.build(ctx, child)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, childNode)
+		}
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		x, y := node.Children[i], node.Children[j]
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		return x.Name < y.Name
+	})
+	return node, nil
+}
+
+// resourceGVRs returns the resources of the kinds created by the resource
+// graph definition. External references are left out, kro doesn't create
+// them.
+func (b *builder) resourceGVRs(ctx context.Context, rgdName string) ([]schema.GroupVersionResource, error) {
+	u, err := b.client.Resource(rgdGVR).Get(ctx, rgdName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource graph definition %s: %w", rgdName, err)
+	}
+	var rgd v1alpha1.ResourceGraphDefinition
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &rgd); err != nil {
+		return nil, fmt.Errorf("failed to convert resource graph definition %s: %w", rgdName, err)
+	}
+
+	seen := make(map[schema.GroupVersionResource]struct{})
+	var gvrs []schema.GroupVersionResource
+	for _, resource := range rgd.Spec.Resources {
+		if resource.ExternalRef != nil || len(resource.Template.Raw) == 0 {
+			continue
+		}
+		var template metav1.TypeMeta
+		if err := json.Unmarshal(resource.Template.Raw, &template); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal resource %s of %s: %w", resource.ID, rgdName, err)
+		}
+		gvk := template.GroupVersionKind()
+		mapping, err := b.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map resource %s of %s: %w", resource.ID, rgdName, err)
+		}
+		if _, ok := seen[mapping.Resource]; ok {
+			continue
+		}
+		seen[mapping.Resource] = struct{}{}
+		gvrs = append(gvrs, mapping.Resource)
+	}
+	return gvrs, nil
+}
+
+func newNode(obj *unstructured.Unstructured) *Node {
+	return &Node{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kro-run/kro/pkg/metadata"
+)
+
+var (
+	configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	parentGVK    = schema.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "Application"}
+	childGVK     = schema.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "Database"}
+)
+
+func newRGD(name string, templates ...map[string]interface{}) *unstructured.Unstructured {
+	var resources []interface{}
+	for i, template := range templates {
+		resources = append(resources, map[string]interface{}{
+			"id":       string(rune('a' + i)),
+			"template": template,
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "ResourceGraphDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       map[string]interface{}{"resources": resources},
+	}}
+}
+
+func newObject(
+	gvk schema.GroupVersionKind, name string, uid types.UID, rgdName string, ownerUID types.UID,
+) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(uid)
+	labels := map[string]string{}
+	if rgdName != "" {
+		labels[metadata.ResourceGraphDefinitionNameLabel] = rgdName
+	}
+	if ownerUID != "" {
+		labels[metadata.InstanceIDLabel] = string(ownerUID)
+	}
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestBuild(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{configMapGVK, parentGVK, childGVK} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+
+	parent := newObject(parentGVK, "parent", "parent-uid", "parent", "")
+	objects := []runtime.Object{
+		newRGD("parent",
+			map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+			map[string]interface{}{"apiVersion": "kro.run/v1alpha1", "kind": "Database"},
+		),
+		newRGD("child",
+			map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+		),
+		parent,
+		// The resources of the parent instance: a config map and a child
+		// instance, labeled by the parent controller.
+		newObject(configMapGVK, "parent-config", "cm-1", "parent", "parent-uid"),
+		newObject(childGVK, "child", "child-uid", "child", "parent-uid"),
+		// The resources of the child instance.
+		newObject(configMapGVK, "child-config-b", "cm-2", "child", "child-uid"),
+		newObject(configMapGVK, "child-config-a", "cm-3", "child", "child-uid"),
+		// Unrelated config map.
+		newObject(configMapGVK, "other", "cm-4", "", ""),
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			rgdGVR:                                  "ResourceGraphDefinitionList",
+			{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+			{Group: "kro.run", Version: "v1alpha1", Resource: "applications"}: "ApplicationList",
+			{Group: "kro.run", Version: "v1alpha1", Resource: "databases"}:    "DatabaseList",
+		},
+		objects...,
+	)
+
+	got, err := Build(context.Background(), client, mapper, parent)
+	require.NoError(t, err)
+
+	want := &Node{
+		GroupVersionKind:        parentGVK,
+		Namespace:               "default",
+		Name:                    "parent",
+		ResourceGraphDefinition: "parent",
+		Children: []*Node{
+			{GroupVersionKind: configMapGVK, Namespace: "default", Name: "parent-config"},
+			{
+				GroupVersionKind:        childGVK,
+				Namespace:               "default",
+				Name:                    "child",
+				ResourceGraphDefinition: "child",
+				Children: []*Node{
+					{GroupVersionKind: configMapGVK, Namespace: "default", Name: "child-config-a"},
+					{GroupVersionKind: configMapGVK, Namespace: "default", Name: "child-config-b"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+	assert.True(t, got.IsInstance())
+	assert.False(t, got.Children[0].IsInstance())
+	assert.True(t, got.Children[1].IsInstance())
+}
+
+func TestBuild_NotAnInstance(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	obj := newObject(configMapGVK, "config", "cm-1", "", "")
+
+	_, err := Build(context.Background(), client, meta.NewDefaultRESTMapper(nil), obj)
+	assert.ErrorContains(t, err, "is not an instance")
+}
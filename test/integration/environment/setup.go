@@ -160,6 +160,7 @@ func (e *Environment) setupController() error {
 		dc,
 		e.GraphBuilder,
 		1,
+		e.ControllerConfig.ReconcileConfig,
 	)
 
 	var err error
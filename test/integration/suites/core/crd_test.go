@@ -28,6 +28,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	kroclient "github.com/kro-run/kro/pkg/client"
+	"github.com/kro-run/kro/pkg/controller/resourcegraphdefinition"
 	"github.com/kro-run/kro/pkg/metadata"
 	"github.com/kro-run/kro/pkg/testutil/generator"
 )
@@ -255,4 +258,139 @@ var _ = Describe("CRD", func() {
 			}, 20*time.Second, 2*time.Second).Should(Succeed())
 		})
 	})
+
+	Context("CRD Conflicts", func() {
+		It("should mark the second ResourceGraphDefinition declaring the same kind as inactive", func() {
+			newRGD := func(name string) *krov1alpha1.ResourceGraphDefinition {
+				return generator.NewResourceGraphDefinition(name,
+					generator.WithSchema(
+						"TestConflict", "v1alpha1",
+						map[string]interface{}{
+							"field1": "string",
+						},
+						nil,
+					),
+				)
+			}
+
+			owner := newRGD("test-crd-conflict-owner")
+			Expect(env.Client.Create(ctx, owner)).To(Succeed())
+
+			// Wait for the first ResourceGraphDefinition to own the kind
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: owner.Name}, owner)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(owner.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			conflicting := newRGD("test-crd-conflict-other")
+			Expect(env.Client.Create(ctx, conflicting)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: conflicting.Name}, conflicting)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(conflicting.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateInactive))
+
+				var condition *krov1alpha1.Condition
+				for _, cond := range conflicting.Status.Conditions {
+					if cond.Type == resourcegraphdefinition.KindReady {
+						condition = &cond
+						break
+					}
+				}
+				g.Expect(condition).ToNot(BeNil())
+				g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(*condition.Reason).To(Equal("CRDConflict"))
+				g.Expect(*condition.Message).To(ContainSubstring(owner.Name))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			// The first ResourceGraphDefinition keeps owning the CRD
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			Expect(env.Client.Get(ctx, types.NamespacedName{Name: "testconflicts.kro.run"}, crd)).To(Succeed())
+			Expect(crd.Labels[metadata.ResourceGraphDefinitionNameLabel]).To(Equal(owner.Name))
+			Expect(env.Client.Get(ctx, types.NamespacedName{Name: owner.Name}, owner)).To(Succeed())
+			Expect(owner.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+
+			// Deleting the conflicting ResourceGraphDefinition leaves the CRD in place
+			Expect(env.Client.Delete(ctx, conflicting)).To(Succeed())
+			Eventually(func() bool {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: conflicting.Name}, conflicting)
+				return errors.IsNotFound(err)
+			}, 10*time.Second, time.Second).Should(BeTrue())
+			Expect(env.Client.Get(ctx, types.NamespacedName{Name: "testconflicts.kro.run"}, crd)).To(Succeed())
+
+			Expect(env.Client.Delete(ctx, owner)).To(Succeed())
+		})
+
+		It("should activate the conflicting ResourceGraphDefinition once the owner is deleted", func() {
+			newRGD := func(name string) *krov1alpha1.ResourceGraphDefinition {
+				return generator.NewResourceGraphDefinition(name,
+					generator.WithSchema(
+						"TestConflictHandover", "v1alpha1",
+						map[string]interface{}{
+							"field1": "string",
+						},
+						nil,
+					),
+				)
+			}
+
+			owner := newRGD("test-crd-handover-owner")
+			Expect(env.Client.Create(ctx, owner)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: owner.Name}, owner)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(owner.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			conflicting := newRGD("test-crd-handover-other")
+			Expect(env.Client.Create(ctx, conflicting)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: conflicting.Name}, conflicting)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(conflicting.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateInactive))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			// Deleting the owner re-enqueues the conflicting ResourceGraphDefinition
+			Expect(env.Client.Delete(ctx, owner)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: conflicting.Name}, conflicting)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(conflicting.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 20*time.Second, time.Second).Should(Succeed())
+
+			Expect(env.Client.Delete(ctx, conflicting)).To(Succeed())
+		})
+	})
+
+	Context("CRD Establishment", func() {
+		It("should not mark the ResourceGraphDefinition Active before the CRD is established", func() {
+			rgd := generator.NewResourceGraphDefinition("test-crd-established",
+				generator.WithSchema(
+					"TestEstablished", "v1alpha1",
+					map[string]interface{}{
+						"field1": "string",
+					},
+					nil,
+				),
+			)
+			Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+			// Whenever the ResourceGraphDefinition is observed as Active, the CRD
+			// must already report the Established condition.
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+				g.Expect(err).ToNot(HaveOccurred())
+				if rgd.Status.State == krov1alpha1.ResourceGraphDefinitionStateActive {
+					crd := &apiextensionsv1.CustomResourceDefinition{}
+					err := env.Client.Get(ctx, types.NamespacedName{Name: "testestablisheds.kro.run"}, crd)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(kroclient.IsEstablished(crd)).To(BeTrue())
+				}
+				g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+
+			Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,178 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Status dependencies", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(env.Client.Create(ctx, ns)).To(Succeed())
+	})
+
+	It("should resolve a resource from the observed status of its dependency", func() {
+		rgd := generator.NewResourceGraphDefinition("test-status-dependency",
+			generator.WithSchema(
+				"TestStatusDependency", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("deployment", map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"spec": map[string]interface{}{
+					"replicas": 2,
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"app": "deployment",
+						},
+					},
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{
+								"app": "deployment",
+							},
+						},
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "main",
+									"image": "nginx",
+								},
+							},
+						},
+					},
+				},
+			}, nil, nil),
+			// The config map depends on a status field that is only populated
+			// once the deployment is created and observed.
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"availableReplicas": "${string(deployment.status.availableReplicas)}",
+				},
+			}, nil, nil),
+		)
+
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+		Eventually(func(g Gomega) {
+			created := &krov1alpha1.ResourceGraphDefinition{}
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, created)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(created.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-status-dependency"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestStatusDependency",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name": name,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The deployment is created on the first reconciliation
+		deployment := &appsv1.Deployment{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, deployment)
+			g.Expect(err).ToNot(HaveOccurred())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// The config map can't be resolved until the deployment status is
+		// populated
+		Consistently(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			return errors.IsNotFound(err)
+		}, 5*time.Second, time.Second).Should(BeTrue())
+
+		deployment.Status.Replicas = 2
+		deployment.Status.ReadyReplicas = 2
+		deployment.Status.AvailableReplicas = 2
+		Expect(env.Client.Status().Update(ctx, deployment)).To(Succeed())
+
+		// A later reconciliation observes the deployment status and resolves
+		// the config map
+		Eventually(func(g Gomega) {
+			configMap := &corev1.ConfigMap{}
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("availableReplicas", "2"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Eventually(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			return errors.IsNotFound(err)
+		}, 20*time.Second, time.Second).Should(BeTrue())
+
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+})
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -179,4 +180,226 @@ var _ = Describe("ExternalRef", func() {
 		Expect(env.Client.Delete(ctx, deployment1)).To(Succeed())
 		Expect(env.Client.Delete(ctx, ns)).To(Succeed())
 	})
+
+	It("should wait for the ExternalRef to be ready before applying dependent resources", func() {
+		ctx := context.Background()
+		namespace := fmt.Sprintf("test-%s", rand.String(5))
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(env.Client.Create(ctx, ns)).To(Succeed())
+
+		// Create the external object, a Deployment that isn't available yet
+		database := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-database",
+				Namespace: namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app": "test-database",
+					},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app": "test-database",
+						},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "database", Image: "postgres"},
+						},
+					},
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, database)).To(Succeed())
+
+		rgd := generator.NewResourceGraphDefinition("test-externalref-readiness",
+			generator.WithSchema(
+				"TestExternalRefReadiness", "v1alpha1",
+				map[string]interface{}{},
+				map[string]interface{}{},
+			),
+			generator.WithExternalRef("database", &krov1alpha1.ExternalRef{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Metadata: krov1alpha1.ExternalRefMetadata{
+					Name:      "test-database",
+					Namespace: namespace,
+				},
+			}, []string{"${database.status.availableReplicas == 1}"}, nil),
+			generator.WithResource("config", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"database": "${database.metadata.name}",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "TestExternalRefReadiness",
+				"metadata": map[string]interface{}{
+					"name":      "foo-instance",
+					"namespace": namespace,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The ConfigMap must not be created while the database isn't ready
+		configMap := &corev1.ConfigMap{}
+		Consistently(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      "foo-instance",
+				Namespace: namespace,
+			}, configMap)
+			return errors.IsNotFound(err)
+		}, 5*time.Second, time.Second).Should(BeTrue())
+
+		// Flip the database status
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      database.Name,
+				Namespace: namespace,
+			}, database)
+			g.Expect(err).ToNot(HaveOccurred())
+			database.Status.Replicas = 1
+			database.Status.ReadyReplicas = 1
+			database.Status.AvailableReplicas = 1
+			g.Expect(env.Client.Status().Update(ctx, database)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		// The ConfigMap is now applied
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      "foo-instance",
+				Namespace: namespace,
+			}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("database", "test-database"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		Expect(env.Client.Delete(ctx, database)).To(Succeed())
+		Expect(env.Client.Delete(ctx, ns)).To(Succeed())
+	})
+
+	It("should report the missing ExternalRef objects", func() {
+		ctx := context.Background()
+		namespace := fmt.Sprintf("test-%s", rand.String(5))
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(env.Client.Create(ctx, ns)).To(Succeed())
+
+		rgd := generator.NewResourceGraphDefinition("test-externalref-missing",
+			generator.WithSchema(
+				"TestExternalRefMissing", "v1alpha1",
+				map[string]interface{}{},
+				map[string]interface{}{},
+			),
+			generator.WithExternalRef("settings", &krov1alpha1.ExternalRef{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Metadata: krov1alpha1.ExternalRefMetadata{
+					Name:      "missing-settings",
+					Namespace: namespace,
+				},
+			}, nil, nil),
+			generator.WithResource("config", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"settings": "${settings.metadata.name}",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "TestExternalRefMissing",
+				"metadata": map[string]interface{}{
+					"name":      "foo-instance",
+					"namespace": namespace,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The instance reports the missing object
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      "foo-instance",
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			conditions, _, err := unstructured.NestedSlice(instance.Object, "status", "conditions")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(conditions).To(ContainElement(And(
+				HaveKeyWithValue("type", "InstanceSynced"),
+				HaveKeyWithValue("status", "False"),
+				HaveKeyWithValue("reason", "ExternalRefNotFound"),
+				HaveKeyWithValue("message", ContainSubstring("v1 ConfigMap %s/missing-settings", namespace)),
+			)))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// Create the missing object, the instance is then reconciled
+		settings := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "missing-settings",
+				Namespace: namespace,
+			},
+		}
+		Expect(env.Client.Create(ctx, settings)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      "foo-instance",
+				Namespace: namespace,
+			}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("settings", "missing-settings"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		Expect(env.Client.Delete(ctx, settings)).To(Succeed())
+		Expect(env.Client.Delete(ctx, ns)).To(Succeed())
+	})
 })
@@ -0,0 +1,122 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Propagation", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	It("should propagate the selected instance labels and annotations", func() {
+		rgd := generator.NewResourceGraphDefinition("test-propagation",
+			generator.WithSchema(
+				"TestPropagation", "v1alpha1",
+				map[string]interface{}{
+					"value": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"value": "${schema.spec.value}",
+				},
+			}, nil, nil),
+		)
+		rgd.Spec.Propagate = &krov1alpha1.Propagation{
+			Labels:      []string{"team"},
+			Annotations: []string{"cost-center"},
+		}
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestPropagation",
+				"metadata": map[string]interface{}{
+					"name":      "test-propagation",
+					"namespace": namespace,
+					"labels": map[string]interface{}{
+						"team":        "platform",
+						"environment": "prod",
+					},
+					"annotations": map[string]interface{}{
+						"cost-center": "1234",
+						"owner":       "someone",
+					},
+				},
+				"spec": map[string]interface{}{
+					"value": "foo",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      "test-propagation",
+				Namespace: namespace,
+			}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(configMap.Labels).To(HaveKeyWithValue("team", "platform"))
+			g.Expect(configMap.Labels).ToNot(HaveKey("environment"))
+			g.Expect(configMap.Annotations).To(HaveKeyWithValue("cost-center", "1234"))
+			g.Expect(configMap.Annotations).ToNot(HaveKey("owner"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+})
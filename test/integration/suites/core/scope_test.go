@@ -0,0 +1,222 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Scope", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	It("should reconcile cluster scoped instances", func() {
+		rgd := generator.NewResourceGraphDefinition("test-cluster-scope",
+			generator.WithSchema(
+				"TestClusterScope", "v1alpha1",
+				map[string]interface{}{
+					"namespace": "string",
+					"value":     "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "${schema.metadata.name}",
+					"namespace": "${schema.spec.namespace}",
+				},
+				"data": map[string]interface{}{
+					"value": "${schema.spec.value}",
+				},
+			}, nil, nil),
+		)
+		rgd.Spec.Schema.Scope = string(apiextensionsv1.ClusterScoped)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(env.Client.Get(ctx, types.NamespacedName{Name: "testclusterscopes.kro.run"}, crd)).To(Succeed())
+		Expect(crd.Spec.Scope).To(Equal(apiextensionsv1.ClusterScoped))
+
+		name := fmt.Sprintf("test-cluster-scope-%s", rand.String(5))
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestClusterScope",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"namespace": namespace,
+					"value":     "foo",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("value", "foo"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			state, _, err := unstructured.NestedString(instance.Object, "status", "state")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(state).To(Equal("ACTIVE"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Eventually(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+			return errors.IsNotFound(err)
+		}, 20*time.Second, time.Second).Should(BeTrue())
+
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+
+	It("should create resources in a namespace derived from the instance spec", func() {
+		rgd := generator.NewResourceGraphDefinition("test-tenant-namespace",
+			generator.WithSchema(
+				"TestTenantNamespace", "v1alpha1",
+				map[string]interface{}{
+					"tenant": "string",
+					"value":  "string",
+				},
+				nil,
+			),
+			generator.WithResource("namespace", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.tenant}",
+				},
+			}, nil, nil),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "${schema.metadata.name}",
+					"namespace": "${namespace.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"value": "${schema.spec.value}",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			g.Expect(rgd.Status.TopologicalOrder).To(Equal([]string{"namespace", "configmap"}))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := fmt.Sprintf("test-tenant-namespace-%s", rand.String(5))
+		tenant := fmt.Sprintf("tenant-%s", rand.String(5))
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestTenantNamespace",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"tenant": tenant,
+					"value":  "foo",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The configmap is created in the tenant namespace, not in the
+		// instance one.
+		configMap := &corev1.ConfigMap{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: tenant}, configMap)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("value", "foo"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			state, _, err := unstructured.NestedString(instance.Object, "status", "state")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(state).To(Equal("ACTIVE"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Eventually(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: tenant}, configMap)
+			return errors.IsNotFound(err)
+		}, 20*time.Second, time.Second).Should(BeTrue())
+
+		// There is no namespace controller in the test environment, the
+		// tenant namespace stays terminating once deleted.
+		Eventually(func(g Gomega) {
+			tenantNamespace := &corev1.Namespace{}
+			err := env.Client.Get(ctx, types.NamespacedName{Name: tenant}, tenantNamespace)
+			if errors.IsNotFound(err) {
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tenantNamespace.DeletionTimestamp).ToNot(BeNil())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+})
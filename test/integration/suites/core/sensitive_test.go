@@ -0,0 +1,192 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Sensitive fields", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	It("should wire a generated Secret into a Deployment", func() {
+		rgd := generator.NewResourceGraphDefinition("test-sensitive",
+			generator.WithSchema(
+				"TestSensitive", "v1alpha1",
+				map[string]interface{}{
+					"name":     "string",
+					"password": "string | sensitive=true",
+				},
+				nil,
+			),
+			generator.WithResource("secret", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}-credentials",
+				},
+				"stringData": map[string]interface{}{
+					"password": "${schema.spec.password}",
+				},
+			}, nil, nil),
+			generator.WithResource("deployment", map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"spec": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"app": "deployment",
+						},
+					},
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{
+								"app": "deployment",
+							},
+						},
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "app",
+									"image": "nginx",
+									"env": []interface{}{
+										map[string]interface{}{
+											"name": "PASSWORD",
+											"valueFrom": map[string]interface{}{
+												"secretKeyRef": map[string]interface{}{
+													"name": "${secret.metadata.name}",
+													"key":  "password",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			// The Secret is applied before the Deployment referencing it
+			g.Expect(rgd.Status.TopologicalOrder).To(Equal([]string{"secret", "deployment"}))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-sensitive"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestSensitive",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name":     name,
+					"password": "s3cr3t",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name + "-credentials", Namespace: namespace}, secret)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(secret.Data).To(HaveKeyWithValue("password", []byte("s3cr3t")))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			envVars := deployment.Spec.Template.Spec.Containers[0].Env
+			g.Expect(envVars).To(HaveLen(1))
+			g.Expect(envVars[0].Value).To(BeEmpty())
+			g.Expect(envVars[0].ValueFrom.SecretKeyRef.Name).To(Equal(secret.Name))
+			g.Expect(envVars[0].ValueFrom.SecretKeyRef.Key).To(Equal("password"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+
+	It("should reject sensitive fields rendered outside of Secrets", func() {
+		rgd := generator.NewResourceGraphDefinition("test-sensitive-leak",
+			generator.WithSchema(
+				"TestSensitiveLeak", "v1alpha1",
+				map[string]interface{}{
+					"password": "string | sensitive=true",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"password": "${schema.spec.password}",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateInactive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+})
@@ -0,0 +1,157 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Status ownership", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	It("should preserve the conditions set by other field managers", func() {
+		rgd := generator.NewResourceGraphDefinition("test-status-ownership",
+			generator.WithSchema(
+				"TestStatusOwnership", "v1alpha1",
+				map[string]interface{}{
+					"value": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"value": "${schema.spec.value}",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-status-ownership"
+		gvr := schema.GroupVersionResource{
+			Group:    krov1alpha1.KRODomainName,
+			Version:  "v1alpha1",
+			Resource: "teststatusownerships",
+		}
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestStatusOwnership",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"value": "foo",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		conditionTypes := func(g Gomega) []string {
+			err := env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			conditions, _, err := unstructured.NestedSlice(instance.Object, "status", "conditions")
+			g.Expect(err).ToNot(HaveOccurred())
+			var conditionTypes []string
+			for _, c := range conditions {
+				conditionTypes = append(conditionTypes, c.(map[string]interface{})["type"].(string))
+			}
+			return conditionTypes
+		}
+		Eventually(func(g Gomega) {
+			g.Expect(conditionTypes(g)).To(ContainElement("InstanceSynced"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// Another controller sets its own condition on the instance
+		_, err := env.ClientSet.Dynamic().Resource(gvr).Namespace(namespace).ApplyStatus(ctx, name,
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": instance.GetAPIVersion(),
+				"kind":       instance.GetKind(),
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               "PolicyCompliant",
+							"status":             "True",
+							"reason":             "Compliant",
+							"message":            "instance is compliant",
+							"lastTransitionTime": time.Now().Format(time.RFC3339),
+						},
+					},
+				},
+			}},
+			metav1.ApplyOptions{FieldManager: "policy-controller"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Force kro to reconcile and update the status of the instance
+		Expect(env.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, instance)).To(Succeed())
+		instance.SetAnnotations(map[string]string{metadata.ReconcileAnnotation: "1"})
+		Expect(env.Client.Update(ctx, instance)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(conditionTypes(g)).To(ContainElements("InstanceSynced", "PolicyCompliant"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Consistently(func(g Gomega) {
+			g.Expect(conditionTypes(g)).To(ContainElements("InstanceSynced", "PolicyCompliant"))
+		}, 5*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+	})
+})
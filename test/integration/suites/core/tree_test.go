@@ -0,0 +1,168 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	"github.com/kro-run/kro/pkg/testutil/generator"
+	"github.com/kro-run/kro/pkg/tree"
+)
+
+var _ = Describe("Tree", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(env.Client.Create(ctx, ns)).To(Succeed())
+	})
+
+	It("should return the objects spawned by nested instances", func() {
+		childRGD := generator.NewResourceGraphDefinition("test-tree-child",
+			generator.WithSchema(
+				"TestTreeChild", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}-child",
+				},
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			}, nil, nil),
+		)
+		parentRGD := generator.NewResourceGraphDefinition("test-tree-parent",
+			generator.WithSchema(
+				"TestTreeParent", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}-parent",
+				},
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			}, nil, nil),
+			generator.WithResource("child", map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestTreeChild",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"spec": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+			}, nil, nil),
+		)
+
+		// The parent resource graph definition needs the child CRD.
+		for _, rgd := range []*krov1alpha1.ResourceGraphDefinition{childRGD, parentRGD} {
+			Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+			Eventually(func(g Gomega) {
+				created := &krov1alpha1.ResourceGraphDefinition{}
+				err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, created)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(created.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 20*time.Second, time.Second).Should(Succeed())
+		}
+
+		name := "test-tree"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestTreeParent",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name": name,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// Wait for the child instance to create its config map
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name + "-child",
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			g.Expect(err).ToNot(HaveOccurred())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			root, err := tree.Build(ctx, env.ClientSet.Dynamic(), env.Client.RESTMapper(), instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(root.ResourceGraphDefinition).To(Equal(parentRGD.Name))
+			g.Expect(root.Children).To(HaveLen(2))
+
+			configMap, child := root.Children[0], root.Children[1]
+			g.Expect(configMap.Kind).To(Equal("ConfigMap"))
+			g.Expect(configMap.Name).To(Equal(name + "-parent"))
+			g.Expect(configMap.IsInstance()).To(BeFalse())
+
+			g.Expect(child.Kind).To(Equal("TestTreeChild"))
+			g.Expect(child.Name).To(Equal(name))
+			g.Expect(child.ResourceGraphDefinition).To(Equal(childRGD.Name))
+			g.Expect(child.Children).To(HaveLen(1))
+			g.Expect(child.Children[0].Kind).To(Equal("ConfigMap"))
+			g.Expect(child.Children[0].Name).To(Equal(name + "-child"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// Cleanup
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, parentRGD)).To(Succeed())
+		Expect(env.Client.Delete(ctx, childRGD)).To(Succeed())
+	})
+})
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
@@ -306,6 +307,66 @@ var _ = Describe("Validation", func() {
 		})
 	})
 
+	Context("Mutually Exclusive Fields", func() {
+		It("should reject instances setting mutually exclusive fields", func() {
+			rgd := generator.NewResourceGraphDefinition("test-mutually-exclusive",
+				generator.WithSchema(
+					"TestMutuallyExclusive", "v1alpha1",
+					map[string]interface{}{
+						"image":    "string",
+						"imageRef": "string",
+					},
+					nil,
+				),
+				generator.WithMutuallyExclusive("image", "imageRef"),
+				generator.WithResource("configmap", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name": "${schema.metadata.name}",
+					},
+				}, nil, nil),
+			)
+			Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			newInstance := func(name string, spec map[string]interface{}) *unstructured.Unstructured {
+				return &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+						"kind":       "TestMutuallyExclusive",
+						"metadata": map[string]interface{}{
+							"name":      name,
+							"namespace": namespace,
+						},
+						"spec": spec,
+					},
+				}
+			}
+
+			both := newInstance("both", map[string]interface{}{
+				"image":    "nginx",
+				"imageRef": "registry/nginx",
+			})
+			err := env.Client.Create(ctx, both)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at most one of image, imageRef can be set"))
+
+			one := newInstance("one", map[string]interface{}{
+				"image": "nginx",
+			})
+			Expect(env.Client.Create(ctx, one)).To(Succeed())
+
+			Expect(env.Client.Delete(ctx, one)).To(Succeed())
+			Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		})
+	})
+
 	Context("Proper Cleanup", func() {
 		It("should not panic when deleting an inactive ResourceGraphDefinition", func() {
 			rgd := generator.NewResourceGraphDefinition("test-cleanup",